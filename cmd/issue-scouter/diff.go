@@ -0,0 +1,153 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+)
+
+type changeKind string
+
+const (
+	changeAdded    changeKind = "A"
+	changeModified changeKind = "M"
+	changeDeleted  changeKind = "D"
+)
+
+type fileChange struct {
+	kind    changeKind
+	path    string
+	added   int
+	removed int
+}
+
+func (c fileChange) String() string {
+	return fmt.Sprintf("%s %s (+%d -%d)", c.kind, c.path, c.added, c.removed)
+}
+
+func diffCommand(args []string) error {
+	fs := newFlagSet("diff", "[flags]")
+	configFile := configFlag(fs)
+	dest := destFlag(fs)
+	token := tokenFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	co, err := loadConfig(configFile.files, *dest)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	c := client.NewClient(co, *token)
+	issues, err := c.FetchIssues()
+	if err != nil {
+		return fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	files, _, err := buildFiles(co, issues, c.Repositories(), now, nil)
+	if err != nil {
+		return err
+	}
+
+	changes, err := diffFiles(files, co.Destination)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		fmt.Println("No changes")
+		return nil
+	}
+	for _, ch := range changes {
+		fmt.Println(ch)
+	}
+	return nil
+}
+
+// diffFiles compares generated files with the ones on disk. Files written
+// by the previous run into dest which are not generated anymore are reported
+// as deleted because saveToFiles removes them.
+func diffFiles(files markdownFiles, dest string) ([]fileChange, error) {
+	var changes []fileChange
+	generated := make(map[string]struct{}, len(files))
+
+	for _, f := range files {
+		generated[filepath.Clean(f.pathRelative)] = struct{}{}
+
+		current, err := os.ReadFile(filepath.Clean(f.pathRelative))
+		if os.IsNotExist(err) {
+			changes = append(changes, fileChange{kind: changeAdded, path: f.pathRelative, added: countLines(f.content)})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", f.pathRelative, err)
+		}
+		if string(current) == f.content {
+			continue
+		}
+		added, removed := lineChanges(string(current), f.content)
+		changes = append(changes, fileChange{kind: changeModified, path: f.pathRelative, added: added, removed: removed})
+	}
+
+	previous, err := previousFiles(dest)
+	if err != nil {
+		return nil, err
+	}
+	for _, rel := range previous {
+		path := filepath.Join(dest, filepath.FromSlash(rel))
+		if _, ok := generated[path]; ok {
+			continue
+		}
+		current, err := os.ReadFile(path)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		changes = append(changes, fileChange{kind: changeDeleted, path: path, removed: countLines(string(current))})
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		return changes[i].path < changes[j].path
+	})
+
+	return changes, nil
+}
+
+func countLines(s string) int {
+	if s == "" {
+		return 0
+	}
+	return len(strings.Split(strings.TrimSuffix(s, "\n"), "\n"))
+}
+
+// lineChanges counts lines which only exist in either before or after,
+// ignoring the order of lines.
+func lineChanges(before, after string) (added, removed int) {
+	counts := make(map[string]int)
+	if before != "" {
+		for _, l := range strings.Split(strings.TrimSuffix(before, "\n"), "\n") {
+			counts[l]++
+		}
+	}
+	if after != "" {
+		for _, l := range strings.Split(strings.TrimSuffix(after, "\n"), "\n") {
+			if counts[l] > 0 {
+				counts[l]--
+				continue
+			}
+			added++
+		}
+	}
+	for _, n := range counts {
+		removed += n
+	}
+	return added, removed
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffFiles(t *testing.T) {
+	tmpDir := t.TempDir()
+	issuesDir := filepath.Join(tmpDir, "issues")
+	assert.NoError(t, os.MkdirAll(issuesDir, 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(issuesDir, "same.md"), []byte("a\nb\n"), 0640))
+	assert.NoError(t, os.WriteFile(filepath.Join(issuesDir, "changed.md"), []byte("a\nb\nc\n"), 0640))
+	assert.NoError(t, os.WriteFile(filepath.Join(issuesDir, "old.md"), []byte("x\ny\n"), 0640))
+
+	files := markdownFiles{
+		{pathRelative: filepath.Join(issuesDir, "same.md"), content: "a\nb\n"},
+		{pathRelative: filepath.Join(issuesDir, "changed.md"), content: "a\nc\nd\ne\n"},
+		{pathRelative: filepath.Join(issuesDir, "new.md"), content: "n\n"},
+	}
+
+	got, err := diffFiles(files, tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []fileChange{
+		{kind: changeModified, path: filepath.Join(issuesDir, "changed.md"), added: 2, removed: 1},
+		{kind: changeAdded, path: filepath.Join(issuesDir, "new.md"), added: 1},
+		{kind: changeDeleted, path: filepath.Join(issuesDir, "old.md"), removed: 2},
+	}, got)
+}
+
+func TestDiffFilesWithoutIssuesDir(t *testing.T) {
+	tmpDir := t.TempDir()
+	files := markdownFiles{
+		{pathRelative: filepath.Join(tmpDir, "README.md"), content: "# Issue List\n"},
+	}
+
+	got, err := diffFiles(files, tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []fileChange{
+		{kind: changeAdded, path: filepath.Join(tmpDir, "README.md"), added: 1},
+	}, got)
+}
+
+func TestDiffFilesWithManifest(t *testing.T) {
+	tmpDir := t.TempDir()
+	issuesDir := filepath.Join(tmpDir, "issues")
+	assert.NoError(t, os.MkdirAll(issuesDir, 0750))
+	assert.NoError(t, os.WriteFile(filepath.Join(issuesDir, "old.md"), []byte("x\n"), 0640))
+	assert.NoError(t, os.WriteFile(filepath.Join(issuesDir, "notes.md"), []byte("mine\n"), 0640))
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, manifestName), []byte(`{"files": ["issues/old.md", "issues/gone.md"]}`), 0640))
+
+	got, err := diffFiles(markdownFiles{}, tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, []fileChange{
+		{kind: changeDeleted, path: filepath.Join(issuesDir, "old.md"), removed: 1},
+	}, got)
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var githubRepoPattern = regexp.MustCompile(`github\.com[/:]([A-Za-z0-9][A-Za-z0-9-]*)/([A-Za-z0-9._-]+)`)
+
+func discoverCommand(args []string) error {
+	fs := newFlagSet("discover", "[flags] <file>... (use - for stdin)")
+	category := fs.String("category", "discovered", "Category name used in the generated repositories block")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no input file specified")
+	}
+
+	var repos []string
+	for _, name := range fs.Args() {
+		var (
+			data []byte
+			err  error
+		)
+		if name == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(filepath.Clean(name))
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", name, err)
+		}
+		repos = append(repos, discoverRepos(string(data))...)
+	}
+
+	slices.Sort(repos)
+	out, err := yaml.Marshal(map[string]map[string][]string{
+		"repositories": {*category: slices.Compact(repos)},
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(out))
+	return nil
+}
+
+// discoverRepos extracts GitHub repository URLs from arbitrary text such as
+// go.mod, Gemfile.lock, package.json or the output of `gem list --details`.
+func discoverRepos(text string) []string {
+	var repos []string
+	for _, m := range githubRepoPattern.FindAllStringSubmatch(text, -1) {
+		repo := strings.TrimSuffix(strings.TrimRight(m[2], "."), ".git")
+		if repo == "" {
+			continue
+		}
+		repos = append(repos, fmt.Sprintf("https://github.com/%s/%s", m[1], repo))
+	}
+	return repos
+}
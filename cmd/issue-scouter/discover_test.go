@@ -0,0 +1,42 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscoverRepos(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{
+			name: "go.mod",
+			text: "require (\n\tgithub.com/google/go-github/v69 v69.2.0\n\tgolang.org/x/oauth2 v0.34.0\n)\n",
+			want: []string{"https://github.com/google/go-github"},
+		},
+		{
+			name: "gem list details",
+			text: "faraday (2.12.2)\n    Homepage: https://github.com/lostisland/faraday.\n",
+			want: []string{"https://github.com/lostisland/faraday"},
+		},
+		{
+			name: "git remote",
+			text: `"repository": "git@github.com:owner/repo.git"`,
+			want: []string{"https://github.com/owner/repo"},
+		},
+		{
+			name: "no repository",
+			text: "https://gitlab.com/owner/repo",
+			want: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, discoverRepos(tt.text))
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+// dedupeIssues applies the duplicates policy to issues found in several
+// categories. Categories are visited in alphabetical order, the order of the
+// index. An issue is always listed once per category.
+func dedupeIssues(policy string, issues client.Issues) {
+	categories := make(map[string][]string)
+	for _, k := range slices.Sorted(maps.Keys(issues)) {
+		kept := issues[k][:0]
+		for _, issue := range issues[k] {
+			url := issue.GetURL()
+			if slices.Contains(categories[url], k) {
+				continue
+			}
+			categories[url] = append(categories[url], k)
+			if policy == config.DuplicatesFirstCategoryWins && len(categories[url]) > 1 {
+				continue
+			}
+			kept = append(kept, issue)
+		}
+		issues[k] = kept
+	}
+
+	if policy != config.DuplicatesCrossReference {
+		return
+	}
+	for k, is := range issues {
+		for _, issue := range is {
+			issue.AlsoIn = nil
+			for _, other := range categories[issue.GetURL()] {
+				if other != k {
+					issue.AlsoIn = append(issue.AlsoIn, other)
+				}
+			}
+		}
+	}
+}
+
+// countUnique returns the number of distinct issues over all categories.
+func countUnique(issues client.Issues) int {
+	seen := make(map[string]struct{})
+	for _, is := range issues {
+		for _, issue := range is {
+			seen[issue.GetURL()] = struct{}{}
+		}
+	}
+	return len(seen)
+}
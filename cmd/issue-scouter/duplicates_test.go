@@ -0,0 +1,79 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func TestDedupeIssues(t *testing.T) {
+	newIssues := func() client.Issues {
+		issue := func(n string) *client.Issue {
+			return &client.Issue{Issue: &github.Issue{URL: github.Ptr("https://github.com/owner/repo/issues/" + n)}}
+		}
+		return client.Issues{
+			"a": {issue("1"), issue("2"), issue("1")},
+			"b": {issue("2"), issue("3")},
+			"c": {issue("2")},
+		}
+	}
+	type listed struct {
+		number string
+		alsoIn []string
+	}
+
+	tests := []struct {
+		name   string
+		policy string
+		want   map[string][]listed
+	}{
+		{
+			name:   "allow",
+			policy: config.DuplicatesAllow,
+			want: map[string][]listed{
+				"a": {{"1", nil}, {"2", nil}},
+				"b": {{"2", nil}, {"3", nil}},
+				"c": {{"2", nil}},
+			},
+		},
+		{
+			name:   "first category wins",
+			policy: config.DuplicatesFirstCategoryWins,
+			want: map[string][]listed{
+				"a": {{"1", nil}, {"2", nil}},
+				"b": {{"3", nil}},
+				"c": {},
+			},
+		},
+		{
+			name:   "cross reference",
+			policy: config.DuplicatesCrossReference,
+			want: map[string][]listed{
+				"a": {{"1", nil}, {"2", []string{"b", "c"}}},
+				"b": {{"2", []string{"a", "c"}}, {"3", nil}},
+				"c": {{"2", []string{"a", "b"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := newIssues()
+			dedupeIssues(tt.policy, issues)
+
+			got := make(map[string][]listed, len(issues))
+			for k, is := range issues {
+				got[k] = []listed{}
+				for _, issue := range is {
+					url := issue.GetURL()
+					got[k] = append(got[k], listed{url[len(url)-1:], issue.AlsoIn})
+				}
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, 3, countUnique(issues))
+		})
+	}
+}
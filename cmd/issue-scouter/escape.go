@@ -0,0 +1,38 @@
+package main
+
+import (
+	"bytes"
+	"strings"
+)
+
+// textEscaper makes text from GitHub safe to embed in a table cell or link
+// text: inline Markdown and HTML are escaped and pipes no longer delimit
+// cells. Line breaks would end the table row, so they become spaces.
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"`", "\\`",
+	"*", `\*`,
+	"_", `\_`,
+	"~", `\~`,
+	"[", `\[`,
+	"]", `\]`,
+	"|", `\|`,
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	"\r\n", " ",
+	"\n", " ",
+	"\r", " ",
+)
+
+// escapeText escapes plain text for a table cell or a link text.
+func escapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// escapeComment makes JSON safe to embed in an HTML comment. A comment must
+// not contain "--", which only appears in JSON strings where it can be
+// written with a unicode escape instead.
+func escapeComment(data []byte) []byte {
+	return bytes.ReplaceAll(data, []byte("--"), []byte(`-\u002d`))
+}
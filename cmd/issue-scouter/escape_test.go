@@ -0,0 +1,119 @@
+package main
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want string
+	}{
+		{"plain", "Fix the parser", "Fix the parser"},
+		{"pipes", "a | b", `a \| b`},
+		{"brackets", "[RFC] Add ]links[", `\[RFC\] Add \]links\[`},
+		{"code", "Rename `foo_bar`", "Rename \\`foo\\_bar\\`"},
+		{"newlines", "line 1\r\nline 2\nline 3", "line 1 line 2 line 3"},
+		{"html", "<script>&", "&lt;script&gt;&amp;"},
+		{"backslash before pipe", `a\|b`, `a\\\|b`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, escapeText(tt.text))
+		})
+	}
+}
+
+func TestEscapeComment(t *testing.T) {
+	data, err := json.Marshal(map[string]string{"body": "<!-- x --> y --!> z ---"})
+	assert.NoError(t, err)
+
+	escaped := escapeComment(data)
+	assert.NotContains(t, string(escaped), "--")
+
+	var got map[string]string
+	assert.NoError(t, json.Unmarshal(escaped, &got))
+	assert.Equal(t, "<!-- x --> y --!> z ---", got["body"])
+}
+
+// splitRow splits a table row into its cells, ignoring escaped pipes.
+func splitRow(row string) []string {
+	var (
+		cells       []string
+		cell        strings.Builder
+		backslashes int
+	)
+	for _, r := range row {
+		if r == '|' && backslashes%2 == 0 {
+			cells = append(cells, cell.String())
+			cell.Reset()
+			continue
+		}
+		if r == '\\' {
+			backslashes++
+		} else {
+			backslashes = 0
+		}
+		cell.WriteRune(r)
+	}
+	cells = append(cells, cell.String())
+	// Drop the text outside of the outer pipes
+	return cells[1 : len(cells)-1]
+}
+
+func FuzzIssueTable(f *testing.F) {
+	f.Add("Fix | parsing", "bug", "body")
+	f.Add("[RFC] `code` ]]", "good first issue", "<!-- x -->")
+	f.Add("line 1\nline 2\r\n", "a|b", "ends with -->")
+	f.Add(`trailing \`, `\`, "--!> --- <script>")
+
+	f.Fuzz(func(t *testing.T, title, label, body string) {
+		c := &config.Config{Excerpt: config.Excerpt{Mode: config.ExcerptColumn, Length: 50}}
+		issue := &client.Issue{Issue: &github.Issue{
+			Title:  github.Ptr(title),
+			Body:   github.Ptr(body),
+			URL:    github.Ptr("https://github.com/owner/repo/issues/1"),
+			Labels: []*github.Label{{Name: github.Ptr(label)}},
+		}}
+		columns := issueColumns(c, issue.GetUpdatedAt().Time)
+
+		var sb strings.Builder
+		writeTable(&sb, columns, []*client.Issue{issue, issue})
+		rows := strings.Split(strings.TrimSuffix(sb.String(), "\n"), "\n")
+		if len(rows) != 4 {
+			t.Fatalf("got %d rows, want 4:\n%s", len(rows), sb.String())
+		}
+		for _, row := range rows {
+			if cells := splitRow(row); len(cells) != len(columns) {
+				t.Fatalf("got %d cells, want %d: %q", len(cells), len(columns), row)
+			}
+		}
+
+		sb.Reset()
+		writeMetadata(&sb, []*client.Issue{issue})
+		comment := strings.TrimSuffix(strings.TrimPrefix(sb.String(), "\n<!--\n"), "\n-->\n")
+		if strings.Contains(comment, "--") {
+			t.Fatalf("comment contains --: %q", comment)
+		}
+		var metadata IssueMetadata
+		if err := json.Unmarshal([]byte(comment), &metadata); err != nil {
+			t.Fatalf("invalid metadata: %v", err)
+		}
+		if utf8.ValidString(title) && metadata.Title != title {
+			t.Fatalf("got title %q, want %q", metadata.Title, title)
+		}
+		if utf8.ValidString(body) && metadata.Body != body {
+			t.Fatalf("got body %q, want %q", metadata.Body, body)
+		}
+	})
+}
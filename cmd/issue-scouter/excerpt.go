@@ -0,0 +1,86 @@
+package main
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	htmlCommentPattern = regexp.MustCompile(`(?s)<!--.*?(-->|$)`)
+	// scriptPattern matches script and style elements, whose content is not
+	// text.
+	scriptPattern    = regexp.MustCompile(`(?is)<script\b[^>]*>.*?(</script\s*>|$)|<style\b[^>]*>.*?(</style\s*>|$)`)
+	codeBlockPattern = regexp.MustCompile("(?s)(```|~~~).*?(```|~~~|$)")
+	imagePattern     = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)|!\[[^\]]*\]\[[^\]]*\]`)
+	linkPattern      = regexp.MustCompile(`\[([^\]]*)\]\([^)]*\)|\[([^\]]*)\]\[[^\]]*\]`)
+	refDefPattern    = regexp.MustCompile(`(?m)^\s*\[[^\]]+\]:\s*\S+.*$`)
+	htmlTagPattern   = regexp.MustCompile(`</?[A-Za-z][^>]*>`)
+	autolinkPattern  = regexp.MustCompile(`<(https?://|mailto:)[^>]*>`)
+	urlPattern       = regexp.MustCompile(`https?://\S+`)
+	// blockPattern matches the markers of headings, quotes, lists and task
+	// lists at the beginning of a line, and the separator rows and outer
+	// pipes of tables.
+	blockPattern = regexp.MustCompile(`(?m)^\s*(#{1,6}\s+|>\s*|[-*+]\s+(\[[ xX]\]\s+)?|\d+[.)]\s+|\|?[-:| ]*-[-:| ]*$|\|)|\|\s*$`)
+	// emphasisPatterns match text between a pair of emphasis, strikethrough
+	// or code span markers, longest markers first. Like in Markdown, the
+	// text does not start or end with a space, so that a lone "*" is kept.
+	emphasisPatterns = []*regexp.Regexp{
+		emphasisPattern(`***`),
+		emphasisPattern(`**`),
+		emphasisPattern(`*`),
+		emphasisPattern(`~~`),
+		emphasisPattern("``"),
+		emphasisPattern("`"),
+	}
+)
+
+func emphasisPattern(marker string) *regexp.Regexp {
+	m := regexp.QuoteMeta(marker)
+	return regexp.MustCompile(m + `(\S|\S.*?\S)` + m)
+}
+
+// excerpt turns a Markdown issue body into a single line of plain text of at
+// most length characters: code blocks, images, links targets, URLs and HTML
+// are removed, and the result is escaped for a table cell.
+func excerpt(body string, length int) string {
+	s := htmlCommentPattern.ReplaceAllString(body, " ")
+	s = scriptPattern.ReplaceAllString(s, " ")
+	s = codeBlockPattern.ReplaceAllString(s, " ")
+	s = imagePattern.ReplaceAllString(s, " ")
+	s = linkPattern.ReplaceAllString(s, "$1$2")
+	s = refDefPattern.ReplaceAllString(s, " ")
+	s = autolinkPattern.ReplaceAllString(s, " ")
+	s = htmlTagPattern.ReplaceAllString(s, " ")
+	s = urlPattern.ReplaceAllString(s, " ")
+	s = blockPattern.ReplaceAllString(s, "")
+	for _, p := range emphasisPatterns {
+		s = p.ReplaceAllString(s, "$1")
+	}
+	s = html.UnescapeString(s)
+	s = strings.Join(strings.Fields(s), " ")
+
+	s = truncate(s, length)
+
+	return escapeText(s)
+}
+
+// truncate cuts s to at most length characters, at a word boundary when
+// possible, marking the cut with an ellipsis.
+func truncate(s string, length int) string {
+	runes := []rune(s)
+	if len(runes) <= length {
+		return s
+	}
+	cut := string(runes[:length-1])
+	// Cut at the last space unless a word ends exactly at the limit
+	if !unicode.IsSpace(runes[length-1]) {
+		if i := strings.LastIndexFunc(cut, unicode.IsSpace); i > len(cut)/2 {
+			cut = cut[:i]
+		}
+	}
+	return strings.TrimRightFunc(cut, func(r rune) bool {
+		return unicode.IsSpace(r) || unicode.IsPunct(r)
+	}) + "…"
+}
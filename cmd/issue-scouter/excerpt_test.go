@@ -0,0 +1,83 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		length int
+		want   string
+	}{
+		{
+			name:   "empty",
+			body:   "",
+			length: 50,
+			want:   "",
+		},
+		{
+			name:   "markdown is stripped",
+			body:   "## Summary\n\nThe **parser** fails on `nil` values.\n\n- [ ] add a test\n> quoted",
+			length: 100,
+			want:   "Summary The parser fails on nil values. add a test quoted",
+		},
+		{
+			name:   "images, links and URLs are removed",
+			body:   "See ![screenshot](https://example.com/a.png) and [the docs](https://example.com/docs) or https://example.com/x.",
+			length: 100,
+			want:   "See and the docs or",
+		},
+		{
+			name:   "code blocks and comments are removed",
+			body:   "<!-- template -->\nSteps:\n```go\nfmt.Println(\"|\")\n```\nthen run it",
+			length: 100,
+			want:   "Steps: then run it",
+		},
+		{
+			name:   "HTML and pipes are sanitized",
+			body:   "<img src=x onerror=alert(1)><b>a | b</b> &lt;script&gt; a_b",
+			length: 100,
+			want:   `a \| b &lt;script&gt; a\_b`,
+		},
+		{
+			name:   "script and style elements are removed with their content",
+			body:   "Crash<script type=\"text/javascript\">alert(\"x\")</script> on <STYLE>p { color: red }</STYLE>load<script>never closed",
+			length: 100,
+			want:   "Crash on load",
+		},
+		{
+			name:   "only paired emphasis markers are removed",
+			body:   "Compute 5 * 3 * 2 with ***all*** of ~~old~~ ``a ` b`` and a*b*c, not ~ or `",
+			length: 100,
+			want:   "Compute 5 \\* 3 \\* 2 with all of old a \\` b and abc, not \\~ or \\`",
+		},
+		{
+			name:   "tables are flattened",
+			body:   "| a | b |\n| --- | --- |\n| 1 | 2 |",
+			length: 100,
+			want:   `a \| b 1 \| 2`,
+		},
+		{
+			name:   "truncated at a word boundary",
+			body:   "The quick brown fox jumps over the lazy dog",
+			length: 20,
+			want:   "The quick brown fox…",
+		},
+		{
+			name:   "truncated by characters",
+			body:   "日本語の説明文がここにあります",
+			length: 5,
+			want:   "日本語の…",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, excerpt(tt.body, tt.length))
+		})
+	}
+}
@@ -0,0 +1,292 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"maps"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+	"github.com/ymtdzzz/issue-scouter/pkg/labels"
+	"gopkg.in/yaml.v3"
+)
+
+type repoLabels struct {
+	Category   string      `json:"category" yaml:"category"`
+	Repository string      `json:"repository" yaml:"repository"`
+	Labels     []labelInfo `json:"labels" yaml:"labels"`
+}
+
+type labelInfo struct {
+	Name        string `json:"name" yaml:"name"`
+	Color       string `json:"color" yaml:"color"`
+	Description string `json:"description" yaml:"description"`
+	// OpenIssues is nil when the issues are not counted.
+	OpenIssues *int `json:"open_issues,omitempty" yaml:"open_issues,omitempty"`
+}
+
+// labelCluster is a cluster of similar labels with the repositories lacking
+// all of them.
+type labelCluster struct {
+	labels.Cluster `yaml:",inline"`
+	Missing        []string `json:"missing" yaml:"missing"`
+}
+
+const labelFormats = "table|json|csv|yaml"
+
+func labelsCommand(args []string) error {
+	fs := newFlagSet("labels", "[flags]")
+	configFile := configFlag(fs)
+	token := tokenFlag(fs)
+	format := formatFlag(fs, "table", labelFormats)
+	match := fs.String("match", "", "Only list labels whose name matches this regular expression, e.g. '(?i)easy|first'")
+	counts := fs.Bool("counts", false, "Count the open issues of each label. This lists every open issue and pull request of the repositories, one API request per 100 of them")
+	cluster := fs.Bool("cluster", false, "Group similar labels across repositories and report which repositories lack them")
+	emit := fs.String("emit", "", "Print a suggested config block instead: labels (every spelling of the configured labels) or aliases (label_aliases for repositories using other spellings)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if !slices.Contains(strings.Split(labelFormats, "|"), *format) {
+		return fmt.Errorf("unsupported format: %s", *format)
+	}
+	if *emit != "" && *emit != "labels" && *emit != "aliases" {
+		return fmt.Errorf("unsupported block to emit: %s", *emit)
+	}
+	var pattern *regexp.Regexp
+	if *match != "" {
+		var err error
+		if pattern, err = regexp.Compile(*match); err != nil {
+			return fmt.Errorf("invalid --match: %w", err)
+		}
+	}
+
+	co, err := loadConfig(configFile.files, "")
+	if err != nil {
+		return err
+	}
+
+	c := client.NewClient(co, *token)
+	ctx := context.Background()
+	// Clusters only need the names and descriptions
+	withCounts := *counts && !*cluster && *emit == ""
+
+	var result []repoLabels
+	for _, k := range slices.Sorted(maps.Keys(co.Repos)) {
+		for _, repo := range co.Repos[k] {
+			owner, repoName, err := config.ParseRepoURL(repo)
+			if err != nil {
+				log.Printf("Failed to parse repository URL %s: %v\n", repo, err)
+				continue
+			}
+
+			ls, err := c.ListLabels(ctx, owner, repoName)
+			if err != nil {
+				log.Printf("Failed to fetch labels: %v\n", err)
+				continue
+			}
+
+			var open map[string]int
+			if withCounts {
+				if open, err = c.CountOpenIssues(ctx, owner, repoName); err != nil {
+					log.Printf("Failed to count open issues: %v\n", err)
+				}
+			}
+
+			rl := repoLabels{
+				Category:   k,
+				Repository: owner + "/" + repoName,
+				Labels:     make([]labelInfo, 0, len(ls)),
+			}
+			for _, label := range ls {
+				if pattern != nil && !pattern.MatchString(label.GetName()) {
+					continue
+				}
+				info := labelInfo{
+					Name:        label.GetName(),
+					Color:       label.GetColor(),
+					Description: label.GetDescription(),
+				}
+				if open != nil {
+					n := open[strings.ToLower(label.GetName())]
+					info.OpenIssues = &n
+				}
+				rl.Labels = append(rl.Labels, info)
+			}
+			result = append(result, rl)
+		}
+	}
+
+	if *cluster || *emit != "" {
+		repos, clusters := clusterLabels(result)
+		switch *emit {
+		case "labels":
+			return printYAML(os.Stdout, map[string][]string{"labels": suggestLabels(co.Labels, clusters)})
+		case "aliases":
+			return printYAML(os.Stdout, map[string]map[string]map[string][]string{
+				"label_aliases": suggestAliases(co.Labels, repos, clusters),
+			})
+		}
+		return printClusters(os.Stdout, *format, repos, clusters)
+	}
+	return printLabels(os.Stdout, *format, result)
+}
+
+func printLabels(w io.Writer, format string, result []repoLabels) error {
+	switch format {
+	case "json":
+		return printJSON(w, result)
+	case "yaml":
+		return printYAML(w, result)
+	case "csv":
+		cw := csv.NewWriter(w)
+		_ = cw.Write([]string{"category", "repository", "name", "color", "description", "open_issues"})
+		for _, rl := range result {
+			for _, l := range rl.Labels {
+				_ = cw.Write([]string{rl.Category, rl.Repository, l.Name, l.Color, l.Description, formatCount(l.OpenIssues)})
+			}
+		}
+		cw.Flush()
+		return cw.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CATEGORY\tREPOSITORY\tLABEL\tOPEN ISSUES\tCOLOR\tDESCRIPTION")
+	for _, rl := range result {
+		for _, l := range rl.Labels {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n", rl.Category, rl.Repository, l.Name, formatCount(l.OpenIssues), l.Color, l.Description)
+		}
+	}
+	return tw.Flush()
+}
+
+func formatCount(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+// clusterLabels groups the labels of every repository, listed once even when
+// configured in several categories.
+func clusterLabels(result []repoLabels) ([]string, []labelCluster) {
+	var (
+		repos []string
+		all   []labels.Label
+	)
+	for _, rl := range result {
+		if slices.Contains(repos, rl.Repository) {
+			continue
+		}
+		repos = append(repos, rl.Repository)
+		for _, l := range rl.Labels {
+			all = append(all, labels.Label{
+				Repository:  rl.Repository,
+				Name:        l.Name,
+				Description: l.Description,
+			})
+		}
+	}
+
+	groups := labels.Group(all)
+	clusters := make([]labelCluster, len(groups))
+	for i, g := range groups {
+		clusters[i] = labelCluster{Cluster: g, Missing: []string{}}
+		for _, r := range repos {
+			if !slices.Contains(g.Repositories, r) {
+				clusters[i].Missing = append(clusters[i].Missing, r)
+			}
+		}
+	}
+	return repos, clusters
+}
+
+func printClusters(w io.Writer, format string, repos []string, clusters []labelCluster) error {
+	switch format {
+	case "json":
+		return printJSON(w, clusters)
+	case "yaml":
+		return printYAML(w, clusters)
+	case "csv":
+		return errors.New("csv format is not supported with --cluster")
+	}
+
+	for _, cl := range clusters {
+		fmt.Fprintf(w, "\n%s (%d/%d repositories)\n", cl.Name, len(cl.Repositories), len(repos))
+		for _, v := range cl.Variants {
+			fmt.Fprintf(w, "  - %s: %s\n", v.Name, strings.Join(v.Repositories, ", "))
+		}
+		if len(cl.Missing) > 0 && len(cl.Missing) < len(repos) {
+			fmt.Fprintf(w, "  missing in: %s\n", strings.Join(cl.Missing, ", "))
+		}
+	}
+	return nil
+}
+
+// suggestLabels returns the configured labels followed by the other
+// spellings of them found in the repositories.
+func suggestLabels(configured []string, clusters []labelCluster) []string {
+	suggested := slices.Clone(configured)
+	for _, label := range configured {
+		for _, cl := range clusters {
+			if !cl.Contains(label) {
+				continue
+			}
+			for _, v := range cl.Variants {
+				if !slices.ContainsFunc(suggested, func(s string) bool { return strings.EqualFold(s, v.Name) }) {
+					suggested = append(suggested, v.Name)
+				}
+			}
+		}
+	}
+	return suggested
+}
+
+// suggestAliases maps the configured labels missing in a repository to the
+// labels with the same intent it defines instead.
+func suggestAliases(configured []string, repos []string, clusters []labelCluster) map[string]map[string][]string {
+	aliases := make(map[string]map[string][]string)
+	for _, label := range configured {
+		for _, cl := range clusters {
+			if !cl.Contains(label) {
+				continue
+			}
+			for _, r := range repos {
+				variants := cl.VariantsIn(r)
+				if len(variants) == 0 || slices.ContainsFunc(variants, func(v string) bool { return strings.EqualFold(v, label) }) {
+					continue
+				}
+				if aliases[r] == nil {
+					aliases[r] = make(map[string][]string)
+				}
+				aliases[r][label] = append(aliases[r][label], variants...)
+			}
+		}
+	}
+	return aliases
+}
+
+func printJSON(w io.Writer, v any) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func printYAML(w io.Writer, v any) error {
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
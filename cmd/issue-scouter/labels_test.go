@@ -0,0 +1,93 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelSuggestions(t *testing.T) {
+	label := func(name string) labelInfo {
+		return labelInfo{Name: name}
+	}
+	result := []repoLabels{
+		{Category: "a", Repository: "owner/a", Labels: []labelInfo{label("good first issue"), label("bug")}},
+		{Category: "a", Repository: "owner/b", Labels: []labelInfo{label("E-easy"), label("Good First Issue")}},
+		{Category: "a", Repository: "owner/c", Labels: []labelInfo{label("beginner")}},
+		{Category: "a", Repository: "owner/d", Labels: []labelInfo{label("bug")}},
+		// Listed in another category, the repository is only counted once
+		{Category: "b", Repository: "owner/c", Labels: []labelInfo{label("beginner")}},
+	}
+
+	repos, clusters := clusterLabels(result)
+	assert.Equal(t, []string{"owner/a", "owner/b", "owner/c", "owner/d"}, repos)
+	assert.Equal(t, "good first issue", clusters[0].Name)
+	assert.Equal(t, []string{"owner/d"}, clusters[0].Missing)
+	assert.Equal(t, "bug", clusters[1].Name)
+	assert.Equal(t, []string{"owner/b", "owner/c"}, clusters[1].Missing)
+
+	assert.Equal(t,
+		[]string{"good first issue", "E-easy", "beginner"},
+		suggestLabels([]string{"good first issue"}, clusters),
+	)
+	assert.Equal(t,
+		map[string]map[string][]string{
+			"owner/c": {"good first issue": {"beginner"}},
+		},
+		suggestAliases([]string{"good first issue"}, repos, clusters),
+	)
+}
+
+func TestPrintLabels(t *testing.T) {
+	result := []repoLabels{
+		{
+			Category:   "a",
+			Repository: "owner/repo",
+			Labels: []labelInfo{
+				{Name: "good first issue", Color: "7057ff", Description: "Good for newcomers", OpenIssues: github.Ptr(3)},
+				{Name: "bug", Color: "d73a4a", Description: "Something, isn't working"},
+			},
+		},
+	}
+
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: "table",
+			want: "CATEGORY  REPOSITORY  LABEL             OPEN ISSUES  COLOR   DESCRIPTION\n" +
+				"a         owner/repo  good first issue  3            7057ff  Good for newcomers\n" +
+				"a         owner/repo  bug                            d73a4a  Something, isn't working\n",
+		},
+		{
+			format: "csv",
+			want: "category,repository,name,color,description,open_issues\n" +
+				"a,owner/repo,good first issue,7057ff,Good for newcomers,3\n" +
+				"a,owner/repo,bug,d73a4a,\"Something, isn't working\",\n",
+		},
+		{
+			format: "yaml",
+			want: "- category: a\n" +
+				"  repository: owner/repo\n" +
+				"  labels:\n" +
+				"    - name: good first issue\n" +
+				"      color: 7057ff\n" +
+				"      description: Good for newcomers\n" +
+				"      open_issues: 3\n" +
+				"    - name: bug\n" +
+				"      color: d73a4a\n" +
+				"      description: Something, isn't working\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var sb strings.Builder
+			assert.NoError(t, printLabels(&sb, tt.format, result))
+			assert.Equal(t, tt.want, sb.String())
+		})
+	}
+}
@@ -0,0 +1,215 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"text/template"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+// llmRecord is a line of the JSON Lines export, describing an issue.
+type llmRecord struct {
+	Category    string          `json:"category"`
+	URL         string          `json:"url"`
+	Title       string          `json:"title"`
+	Body        string          `json:"body"`
+	Labels      []string        `json:"labels"`
+	CreatedAt   string          `json:"created_at"`
+	UpdatedAt   string          `json:"updated_at"`
+	Comments    int             `json:"comments"`
+	Score       float64         `json:"score,omitempty"`
+	Repository  *llmRepository  `json:"repository,omitempty"`
+	Recent      []llmComment    `json:"recent_comments,omitempty"`
+	PullRequest *llmPullRequest `json:"pull_request,omitempty"`
+}
+
+type llmRepository struct {
+	FullName    string `json:"full_name"`
+	Description string `json:"description,omitempty"`
+	Language    string `json:"language,omitempty"`
+	Stars       int    `json:"stars"`
+	License     string `json:"license,omitempty"`
+}
+
+type llmComment struct {
+	Author            string `json:"author"`
+	AuthorAssociation string `json:"author_association"`
+	CreatedAt         string `json:"created_at"`
+	Body              string `json:"body"`
+}
+
+type llmPullRequest struct {
+	Draft        bool   `json:"draft"`
+	ReviewStatus string `json:"review_status,omitempty"`
+}
+
+// llmFields describes the fields of the records for the prompt.
+var llmFields = []struct{ Name, Description string }{
+	{"category", "category of the repository in the configuration"},
+	{"url", "link to the issue on GitHub"},
+	{"title", "title of the issue"},
+	{"body", "description of the issue, in Markdown"},
+	{"labels", "names of the labels"},
+	{"created_at", "creation time, RFC 3339"},
+	{"updated_at", "last update time, RFC 3339"},
+	{"comments", "total number of comments"},
+	{"score", "suitability for new contributors from 0 to 100, when scoring is enabled"},
+	{"repository", "full name, description, primary language, stars and license of the repository"},
+	{"recent_comments", "most recent comments, oldest first, with author, author association, time and body"},
+	{"pull_request", "draft and review status, only for pull requests"},
+}
+
+// tokensPerChar is a rough estimate of the number of tokens in English text
+// and code.
+const tokensPerChar = 0.25
+
+const defaultPromptTemplate = `# Issues to review
+
+The files below list open issues from GitHub, one JSON object per line:
+{{range .Files}}
+- {{.}}{{end}}
+
+Categories: {{range $i, $c := .Categories}}{{if $i}}, {{end}}{{$c}}{{end}}.
+
+Each record has the following fields:
+{{range .Fields}}
+- ` + "`{{.Name}}`" + `: {{.Description}}{{end}}
+
+Suggest the issues best suited to a first contribution. For each of them,
+give the URL, a one-sentence summary of the work and why it is approachable.
+Skip issues which look claimed or in progress according to their comments.
+`
+
+// generateLLMExport writes the issues of each category as JSON Lines, split
+// into chunks of about llm.max_tokens tokens, and a prompt describing them.
+func generateLLMExport(c *config.Config, issues client.Issues) (markdownFiles, error) {
+	dir := filepath.Join(c.Destination, c.LLM.Directory)
+
+	var (
+		files markdownFiles
+		names []string
+	)
+	categories := slices.Sorted(maps.Keys(issues))
+	slugs := config.UniqueSlugs(categories, "category")
+	for _, k := range categories {
+		var chunks []string
+		var sb, chunk bytes.Buffer
+		for _, issue := range issues[k] {
+			sb.Reset()
+			enc := json.NewEncoder(&sb)
+			enc.SetEscapeHTML(false)
+			if err := enc.Encode(newLLMRecord(k, issue)); err != nil {
+				return nil, fmt.Errorf("failed to encode %s: %w", issue.GetHTMLURL(), err)
+			}
+			// A record larger than the budget gets a chunk of its own
+			if c.LLM.MaxTokens > 0 && chunk.Len() > 0 && estimateTokens(chunk.String())+estimateTokens(sb.String()) > c.LLM.MaxTokens {
+				chunks = append(chunks, chunk.String())
+				chunk.Reset()
+			}
+			chunk.Write(sb.Bytes())
+		}
+		chunks = append(chunks, chunk.String())
+
+		for i, content := range chunks {
+			name := slugs[k] + ".jsonl"
+			if c.LLM.MaxTokens > 0 {
+				name = fmt.Sprintf("%s-%03d.jsonl", slugs[k], i+1)
+			}
+			names = append(names, name)
+			files = append(files, markdownFile{
+				pathRelative: filepath.Join(dir, name),
+				content:      content,
+			})
+		}
+	}
+
+	prompt, err := renderPrompt(c.LLM.PromptTemplate, names, categories)
+	if err != nil {
+		return nil, err
+	}
+	files = append(files, markdownFile{
+		pathRelative: filepath.Join(dir, "prompt.md"),
+		content:      prompt,
+	})
+
+	return files, nil
+}
+
+func newLLMRecord(category string, issue *client.Issue) llmRecord {
+	r := llmRecord{
+		Category:  category,
+		URL:       issue.GetHTMLURL(),
+		Title:     issue.GetTitle(),
+		Body:      issue.GetBody(),
+		Labels:    make([]string, 0, len(issue.Labels)),
+		CreatedAt: issue.GetCreatedAt().Format(time.RFC3339),
+		UpdatedAt: issue.GetUpdatedAt().Format(time.RFC3339),
+		Comments:  issue.GetComments(),
+		Score:     issue.Score,
+	}
+	for _, l := range issue.Labels {
+		r.Labels = append(r.Labels, l.GetName())
+	}
+	if repo := issue.Repo; repo != nil {
+		r.Repository = &llmRepository{
+			FullName:    repo.FullName(),
+			Description: repo.Description,
+			Language:    repo.Language,
+			Stars:       repo.Stars,
+			License:     repo.License,
+		}
+	}
+	for _, comment := range issue.RecentComments {
+		r.Recent = append(r.Recent, llmComment{
+			Author:            comment.GetUser().GetLogin(),
+			AuthorAssociation: comment.GetAuthorAssociation(),
+			CreatedAt:         comment.GetCreatedAt().Format(time.RFC3339),
+			Body:              comment.GetBody(),
+		})
+	}
+	if pr := issue.PullRequest; pr != nil {
+		r.PullRequest = &llmPullRequest{Draft: pr.Draft, ReviewStatus: pr.ReviewStatus}
+	}
+	return r
+}
+
+func estimateTokens(s string) int {
+	return int(float64(utf8.RuneCountInString(s))*tokensPerChar) + 1
+}
+
+// renderPrompt renders the prompt template file, or the default prompt when
+// path is empty.
+func renderPrompt(path string, files, categories []string) (string, error) {
+	text := defaultPromptTemplate
+	if path != "" {
+		b, err := os.ReadFile(filepath.Clean(path))
+		if err != nil {
+			return "", fmt.Errorf("failed to read prompt template: %w", err)
+		}
+		text = string(b)
+	}
+	tmpl, err := template.New("prompt").Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse prompt template: %w", err)
+	}
+
+	var sb bytes.Buffer
+	err = tmpl.Execute(&sb, map[string]any{
+		"Files":      files,
+		"Categories": categories,
+		"Fields":     llmFields,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to render prompt template: %w", err)
+	}
+	return sb.String(), nil
+}
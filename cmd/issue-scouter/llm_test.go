@@ -0,0 +1,114 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func TestGenerateLLMExport(t *testing.T) {
+	fixedTime := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	newIssue := func(n int, body string) *client.Issue {
+		return &client.Issue{
+			Issue: &github.Issue{
+				Title:     github.Ptr("Issue"),
+				Body:      github.Ptr(body),
+				HTMLURL:   github.Ptr("https://github.com/owner/repo/issues/" + strconv.Itoa(n)),
+				CreatedAt: &github.Timestamp{Time: fixedTime},
+				UpdatedAt: &github.Timestamp{Time: fixedTime},
+				Labels:    []*github.Label{{Name: github.Ptr("good first issue")}},
+				Comments:  github.Ptr(1),
+			},
+			Repo: &client.Repository{Owner: "owner", Name: "repo", Language: "Go", Stars: 10},
+			RecentComments: []*github.IssueComment{{
+				User:              &github.User{Login: github.Ptr("maintainer")},
+				AuthorAssociation: github.Ptr("MEMBER"),
+				CreatedAt:         &github.Timestamp{Time: fixedTime},
+				Body:              github.Ptr("<b>Thanks</b>"),
+			}},
+		}
+	}
+	record := func(n int, body string) string {
+		return `{"category":"team-a","url":"https://github.com/owner/repo/issues/` + strconv.Itoa(n) + `","title":"Issue","body":"` + body + `",` +
+			`"labels":["good first issue"],"created_at":"2025-03-09T10:00:00Z","updated_at":"2025-03-09T10:00:00Z","comments":1,` +
+			`"repository":{"full_name":"owner/repo","language":"Go","stars":10},` +
+			`"recent_comments":[{"author":"maintainer","author_association":"MEMBER","created_at":"2025-03-09T10:00:00Z","body":"<b>Thanks</b>"}]}` + "\n"
+	}
+	long := strings.Repeat("x", 400)
+	issues := client.Issues{
+		"team-a": []*client.Issue{newIssue(1, "short"), newIssue(2, long), newIssue(3, "short")},
+	}
+
+	tests := []struct {
+		name      string
+		maxTokens int
+		want      map[string]string
+	}{
+		{
+			name: "one file per category",
+			want: map[string]string{
+				"output/llm/team-a.jsonl": record(1, "short") + record(2, long) + record(3, "short"),
+			},
+		},
+		{
+			name:      "chunks by tokens",
+			maxTokens: 150,
+			want: map[string]string{
+				"output/llm/team-a-001.jsonl": record(1, "short"),
+				"output/llm/team-a-002.jsonl": record(2, long),
+				"output/llm/team-a-003.jsonl": record(3, "short"),
+			},
+		},
+		{
+			name:      "records fitting the budget share a chunk",
+			maxTokens: 1000,
+			want: map[string]string{
+				"output/llm/team-a-001.jsonl": record(1, "short") + record(2, long) + record(3, "short"),
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &config.Config{
+				Destination: "output",
+				LLM:         config.LLM{Enabled: true, Directory: "llm", MaxTokens: tt.maxTokens},
+			}
+			files, err := generateLLMExport(c, issues)
+			assert.NoError(t, err)
+
+			got := make(map[string]string)
+			for _, f := range files[:len(files)-1] {
+				got[f.pathRelative] = f.content
+			}
+			assert.Equal(t, tt.want, got)
+
+			prompt := files[len(files)-1]
+			assert.Equal(t, "output/llm/prompt.md", prompt.pathRelative)
+			for name := range tt.want {
+				assert.Contains(t, prompt.content, "- "+filepath.Base(name)+"\n")
+			}
+			assert.Contains(t, prompt.content, "- `recent_comments`: ")
+		})
+	}
+}
+
+func TestRenderPromptTemplate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "prompt.tmpl")
+	assert.NoError(t, os.WriteFile(path, []byte("{{range .Files}}{{.}} {{end}}for {{len .Categories}} categories\n"), 0640))
+
+	got, err := renderPrompt(path, []string{"a.jsonl", "b.jsonl"}, []string{"a", "b"})
+	assert.NoError(t, err)
+	assert.Equal(t, "a.jsonl b.jsonl for 2 categories\n", got)
+
+	_, err = renderPrompt(filepath.Join(t.TempDir(), "missing"), nil, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []*command{
+	{name: "run", summary: "Fetch issues and write the issue list", run: runCommand},
+	{name: "labels", summary: "List labels of the configured repositories", run: labelsCommand},
+	{name: "validate", summary: "Validate the configuration file", run: validateCommand},
+	{name: "diff", summary: "Show how the issue list would change without writing it", run: diffCommand},
+	{name: "discover", summary: "Find GitHub repositories referenced in dependency files", run: discoverCommand},
+	{name: "schema", summary: "Print the JSON Schema of the configuration file", run: schemaCommand},
+}
+
+func main() {
+	if err := execute(os.Args[1:], os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		log.Fatal(err)
+	}
+}
+
+func execute(args []string, stderr io.Writer) error {
+	if len(args) == 0 {
+		printUsage(stderr)
+		return flag.ErrHelp
+	}
+
+	name := args[0]
+	switch name {
+	case "help", "-h", "-help", "--help":
+		printUsage(stderr)
+		return nil
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(args[1:])
+		}
+	}
+
+	printUsage(stderr)
+	return fmt.Errorf("unknown command: %s", name)
+}
+
+func printUsage(w io.Writer) {
+	fmt.Fprintf(w, "Usage: issue-scouter <command> [flags]\n\nCommands:\n")
+	for _, c := range commands {
+		fmt.Fprintf(w, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintf(w, "\nRun 'issue-scouter <command> --help' for the flags of each command.\n")
+	fmt.Fprintf(w, "Flags fall back to the INPUT_* environment variables set by GitHub Actions.\n")
+}
+
+func newFlagSet(name, usage string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: issue-scouter %s %s\n\nFlags:\n", name, usage)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// inputEnv returns the value of the GitHub Action input as exposed through
+// the INPUT_<NAME> environment variable, or fallback if it is not set.
+func inputEnv(name, fallback string) string {
+	if v, ok := os.LookupEnv("INPUT_" + strings.ToUpper(name)); ok && v != "" {
+		return v
+	}
+	return fallback
+}
+
+// fileList is a flag which can be repeated or given as a comma separated list.
+// Values taken from the environment are replaced by the first flag given.
+type fileList struct {
+	files   []string
+	fromEnv bool
+}
+
+func (l *fileList) String() string {
+	if l == nil {
+		return ""
+	}
+	return strings.Join(l.files, ",")
+}
+
+func (l *fileList) Set(v string) error {
+	if l.fromEnv {
+		l.files, l.fromEnv = nil, false
+	}
+	l.files = append(l.files, splitList(v)...)
+	return nil
+}
+
+func splitList(v string) []string {
+	var items []string
+	for _, item := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func configFlag(fs *flag.FlagSet) *fileList {
+	l := &fileList{files: splitList(inputEnv("config_file", "")), fromEnv: true}
+	fs.Var(l, "config", "YAML configuration file, can be repeated or comma separated to merge several files (env: INPUT_CONFIG_FILE)")
+	return l
+}
+
+func destFlag(fs *flag.FlagSet) *string {
+	return fs.String("dest", inputEnv("destination", ""), "Output directory, overrides destination in the config (env: INPUT_DESTINATION)")
+}
+
+func tokenFlag(fs *flag.FlagSet) *string {
+	return fs.String("token", inputEnv("token", os.Getenv("GITHUB_TOKEN")), "GitHub token (env: INPUT_TOKEN or GITHUB_TOKEN)")
+}
+
+func formatFlag(fs *flag.FlagSet, fallback, choices string) *string {
+	return fs.String("format", inputEnv("format", fallback), "Output format: "+choices+" (env: INPUT_FORMAT)")
+}
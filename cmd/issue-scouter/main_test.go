@@ -0,0 +1,88 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []string
+		wantErr error
+		wantMsg string
+	}{
+		{
+			name:    "no command",
+			args:    nil,
+			wantErr: flag.ErrHelp,
+		},
+		{
+			name:    "help",
+			args:    []string{"--help"},
+			wantErr: nil,
+		},
+		{
+			name:    "unknown command",
+			args:    []string{"unknown"},
+			wantMsg: "unknown command: unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stderr bytes.Buffer
+			err := execute(tt.args, &stderr)
+			if tt.wantMsg != "" {
+				assert.EqualError(t, err, tt.wantMsg)
+			} else {
+				assert.Equal(t, tt.wantErr, err)
+			}
+			assert.Contains(t, stderr.String(), "Usage: issue-scouter <command> [flags]")
+		})
+	}
+}
+
+func TestInputEnv(t *testing.T) {
+	t.Setenv("INPUT_CONFIG_FILE", "config.yml")
+	t.Setenv("INPUT_FORMAT", "")
+
+	assert.Equal(t, "config.yml", inputEnv("config_file", ""))
+	assert.Equal(t, "text", inputEnv("format", "text"))
+	assert.Equal(t, "fallback", inputEnv("unknown", "fallback"))
+}
+
+func TestConfigFlag(t *testing.T) {
+	tests := []struct {
+		name string
+		env  string
+		args []string
+		want []string
+	}{
+		{
+			name: "from environment",
+			env:  "base.yml,\nteam.yml",
+			args: nil,
+			want: []string{"base.yml", "team.yml"},
+		},
+		{
+			name: "flags replace environment",
+			env:  "base.yml",
+			args: []string{"--config", "a.yml,b.yml", "--config", "c.yml"},
+			want: []string{"a.yml", "b.yml", "c.yml"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("INPUT_CONFIG_FILE", tt.env)
+			fs := newFlagSet("test", "")
+			l := configFlag(fs)
+			assert.NoError(t, fs.Parse(tt.args))
+			assert.Equal(t, tt.want, l.files)
+		})
+	}
+}
@@ -0,0 +1,687 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"maps"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+type IssueMetadata struct {
+	Title     string            `json:"title"`
+	Body      string            `json:"body"`
+	Labels    []LabelMetadata   `json:"labels"`
+	Assignee  AssigneeMetadata  `json:"assignee,omitempty"`
+	Comments  int               `json:"comments"`
+	Reactions ReactionsMetadata `json:"reactions"`
+	UpdatedAt string            `json:"updated_at"`
+	URL       string            `json:"url"`
+	Activity  *ActivityMetadata `json:"activity,omitempty"`
+}
+
+type ActivityMetadata struct {
+	LastCommenter       string `json:"last_commenter"`
+	MaintainerReplied   bool   `json:"maintainer_replied"`
+	LastMaintainerReply string `json:"last_maintainer_reply,omitempty"`
+	Claimed             bool   `json:"claimed"`
+	ClaimedBy           string `json:"claimed_by,omitempty"`
+	ClaimAcknowledged   bool   `json:"claim_acknowledged,omitempty"`
+}
+
+type ReactionsMetadata struct {
+	Total   int `json:"total"`
+	PlusOne int `json:"+1"`
+}
+
+type LabelMetadata struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+type AssigneeMetadata struct {
+	Login string `json:"login"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+func generateMarkdown(c *config.Config, issues client.Issues, repos client.Repositories) markdownFiles {
+	var (
+		sb, sbi strings.Builder
+		files   markdownFiles
+	)
+
+	sbi.WriteString("# Issue List\n\n")
+	sbi.WriteString(fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")))
+	sbi.WriteString(fmt.Sprintf("\n%s\n\n", c.Description))
+
+	basePath := c.Destination
+	columns := issueColumns(c, time.Now())
+	byCategory := c.HasView(config.ViewCategory)
+	slugs := config.UniqueSlugs(slices.Sorted(maps.Keys(issues)), "category")
+
+	if byCategory {
+		sbi.WriteString("## Index\n\n")
+
+		pageCounts := make(map[string]int, len(issues))
+		for k, is := range issues {
+			pageCounts[k] = len(paginate(is, c.MaxIssuesPerPage))
+		}
+
+		for _, k := range slices.Sorted(maps.Keys(issues)) {
+			pages := paginate(issues[k], c.MaxIssuesPerPage)
+
+			// Add an entry to index
+			var shared string
+			if n := countShared(issues[k]); n > 0 {
+				shared = fmt.Sprintf(", %d also in other categories", n)
+			}
+			sbi.WriteString(fmt.Sprintf("- [%s - %s%s](./issues/%s)%s\n", escapeText(k), availability(c, issues[k]), shared, pathEscape(categoryPage(slugs[k], 1, len(pages))), pageLinks("./issues/", slugs[k], len(pages))))
+
+			// Pages of a paginated category are one directory deeper
+			prefix := "./"
+			if len(pages) > 1 {
+				prefix = "../"
+			}
+			categoryColumns := columns
+			if c.Duplicates == config.DuplicatesCrossReference {
+				categoryColumns = append(slices.Clip(columns), alsoInColumn(prefix, pageCounts, slugs))
+			}
+
+			for i, page := range pages {
+				sb.Reset()
+				sb.WriteString(fmt.Sprintf("# %s\n\n", escapeText(k)))
+				nav := pageNavigation(i+1, len(pages))
+				sb.WriteString(nav)
+
+				if c.Layout == config.LayoutGrouped {
+					writeGroupedItems(&sb, c, categoryColumns, page)
+				} else {
+					writeItems(&sb, c, categoryColumns, page, 2)
+				}
+
+				if c.RepoSummary && len(repos[k]) > 0 && i == 0 {
+					writeRepoSummary(&sb, repos[k])
+				}
+
+				sb.WriteString(nav)
+
+				if c.IncludeMetadata {
+					writeMetadata(&sb, page)
+				}
+
+				files = append(files, markdownFile{
+					pathRelative: fmt.Sprintf("%s/issues/%s", basePath, categoryPage(slugs[k], i+1, len(pages))),
+					content:      sb.String(),
+				})
+			}
+		}
+
+		// The counts of the categories add up to more than the total when an
+		// issue is listed in several of them, whatever the policy
+		if len(issues) > 1 {
+			sbi.WriteString(fmt.Sprintf("\n%d unique issues in total\n", countUnique(issues)))
+		}
+	}
+
+	if c.HasView(config.ViewLanguage) {
+		if byCategory {
+			sbi.WriteString("\n")
+		}
+		sbi.WriteString("## Languages\n\n")
+
+		byLanguage := groupByLanguage(issues, c.SortKeys())
+		languages := slices.Sorted(maps.Keys(byLanguage))
+		languageSlugs := config.UniqueSlugs(languages, unknownLanguage)
+		for _, lang := range languages {
+			// Languages are paginated like categories, the unknown language
+			// may gather as many issues as the largest category
+			pages := paginate(byLanguage[lang], c.MaxIssuesPerPage)
+			slug := languageSlugs[lang]
+			sbi.WriteString(fmt.Sprintf("- [%s - %s](./issues/languages/%s)%s\n", escapeText(lang), availability(c, byLanguage[lang]), pathEscape(categoryPage(slug, 1, len(pages))), pageLinks("./issues/languages/", slug, len(pages))))
+
+			for i, page := range pages {
+				sb.Reset()
+				sb.WriteString(fmt.Sprintf("# %s\n\n", escapeText(lang)))
+				nav := pageNavigation(i+1, len(pages))
+				sb.WriteString(nav)
+
+				writeItems(&sb, c, columns, page, 2)
+
+				sb.WriteString(nav)
+
+				if c.IncludeMetadata {
+					writeMetadata(&sb, page)
+				}
+
+				files = append(files, markdownFile{
+					pathRelative: fmt.Sprintf("%s/issues/languages/%s", basePath, categoryPage(slug, i+1, len(pages))),
+					content:      sb.String(),
+				})
+			}
+		}
+	}
+
+	if c.Scoring.Enabled && c.Scoring.TopPicks > 0 {
+		picks := topPicks(issues, c.Scoring.TopPicks)
+		if len(picks) > 0 {
+			sbi.WriteString("\n## Top Picks\n\n")
+			sbi.WriteString("| Score | Category | Repository | Title |\n")
+			sbi.WriteString("| --- | --- | --- | --- |\n")
+			for _, p := range picks {
+				owner, repoName, _ := config.ParseRepoURL(p.issue.GetURL())
+				category := escapeText(p.category)
+				if byCategory {
+					pages := paginate(issues[p.category], c.MaxIssuesPerPage)
+					page := 1
+					if c.MaxIssuesPerPage > 0 {
+						page = slices.Index(issues[p.category], p.issue)/c.MaxIssuesPerPage + 1
+					}
+					category = fmt.Sprintf("[%s](./issues/%s)", escapeText(p.category), pathEscape(categoryPage(slugs[p.category], page, len(pages))))
+				}
+				sbi.WriteString(fmt.Sprintf(
+					"| %.1f | %s | [%s](https://github.com/%s/%s) | [%s](%s) |\n",
+					p.issue.Score,
+					category,
+					escapeText(repoName),
+					owner,
+					repoName,
+					escapeText(p.issue.GetTitle()),
+					p.issue.GetURL(),
+				))
+			}
+		}
+	}
+
+	if c.Stats.Enabled {
+		sbi.WriteString("\n## Statistics\n\n")
+		sbi.WriteString(fmt.Sprintf("- [Trends of the last %d weeks](./%s)\n", c.Stats.Weeks, statsFile))
+	}
+
+	files = append(files, markdownFile{
+		pathRelative: fmt.Sprintf("%s/README.md", basePath),
+		content:      sbi.String(),
+	})
+
+	return files
+}
+
+// unknownLanguage groups the issues of repositories without a primary
+// language.
+const unknownLanguage = "Unknown"
+
+// groupByLanguage regroups the issues of all categories by the primary
+// language of their repository, sorted by keys. Issues found in several
+// categories are listed once.
+func groupByLanguage(issues client.Issues, keys []config.SortKey) map[string][]*client.Issue {
+	grouped := make(map[string][]*client.Issue)
+	seen := make(map[string]bool)
+	for _, k := range slices.Sorted(maps.Keys(issues)) {
+		for _, issue := range issues[k] {
+			if seen[issue.GetURL()] {
+				continue
+			}
+			seen[issue.GetURL()] = true
+
+			lang := unknownLanguage
+			if issue.Repo != nil && issue.Repo.Language != "" {
+				lang = issue.Repo.Language
+			}
+			grouped[lang] = append(grouped[lang], issue)
+		}
+	}
+
+	for _, list := range grouped {
+		sortByKeys(list, keys)
+	}
+	return grouped
+}
+
+// writeMetadata embeds the metadata of each issue as JSON in HTML comments.
+func writeMetadata(sb *strings.Builder, issues []*client.Issue) {
+	for _, issue := range issues {
+		metadata := IssueMetadata{
+			Title: issue.GetTitle(),
+			Body:  issue.GetBody(),
+			Labels: func() []LabelMetadata {
+				labels := make([]LabelMetadata, len(issue.Labels))
+				for i, l := range issue.Labels {
+					labels[i] = LabelMetadata{
+						Name:        l.GetName(),
+						Color:       l.GetColor(),
+						Description: l.GetDescription(),
+					}
+				}
+				return labels
+			}(),
+			Comments: issue.GetComments(),
+			Reactions: ReactionsMetadata{
+				Total:   issue.GetReactions().GetTotalCount(),
+				PlusOne: issue.GetReactions().GetPlusOne(),
+			},
+			UpdatedAt: issue.GetUpdatedAt().Time.Format(time.RFC3339),
+			URL:       issue.GetURL(),
+		}
+		if issue.Assignee != nil {
+			metadata.Assignee = AssigneeMetadata{
+				Login: issue.Assignee.GetLogin(),
+				Name:  issue.Assignee.GetName(),
+				Email: issue.Assignee.GetEmail(),
+			}
+		}
+		if a := issue.Activity; a != nil {
+			metadata.Activity = &ActivityMetadata{
+				LastCommenter:     a.LastCommenter,
+				MaintainerReplied: a.MaintainerReplied,
+				Claimed:           a.Claim != nil,
+			}
+			if a.MaintainerReplied {
+				metadata.Activity.LastMaintainerReply = a.LastMaintainerReply.Format(time.RFC3339)
+			}
+			if a.Claim != nil {
+				metadata.Activity.ClaimedBy = a.Claim.Claimer
+				metadata.Activity.ClaimAcknowledged = a.Claim.Acknowledged
+			}
+		}
+		jsonData, err := json.MarshalIndent(metadata, "", "  ")
+		if err != nil {
+			log.Printf("Failed to marshal metadata for issue %s: %v", issue.GetTitle(), err)
+			continue
+		}
+		sb.WriteString("\n<!--\n")
+		sb.Write(escapeComment(jsonData))
+		sb.WriteString("\n-->\n")
+	}
+}
+
+type column struct {
+	header string
+	value  func(issue *client.Issue) string
+}
+
+// alsoInColumn links the first page of the other categories listing the
+// issue, relative to the issues directory through prefix. It is only relevant
+// on category pages.
+func alsoInColumn(prefix string, pageCounts map[string]int, slugs map[string]string) column {
+	return column{"Also In", func(issue *client.Issue) string {
+		links := make([]string, len(issue.AlsoIn))
+		for i, k := range issue.AlsoIn {
+			links[i] = fmt.Sprintf("[%s](%s%s)", escapeText(k), prefix, pathEscape(categoryPage(slugs[k], 1, pageCounts[k])))
+		}
+		return strings.Join(links, ", ")
+	}}
+}
+
+// paginate splits items into pages of at most perPage items. There is always
+// at least one page, and a single one when perPage is 0.
+func paginate(items []*client.Issue, perPage int) [][]*client.Issue {
+	if perPage <= 0 || len(items) <= perPage {
+		return [][]*client.Issue{items}
+	}
+	var pages [][]*client.Issue
+	for i := 0; i < len(items); i += perPage {
+		pages = append(pages, items[i:min(i+perPage, len(items))])
+	}
+	return pages
+}
+
+// categoryPage returns the path of a page of the category, relative to the
+// issues directory: <slug>.md, or <slug>/page-<n>.md when the category has
+// several pages.
+func categoryPage(slug string, page, pages int) string {
+	if pages <= 1 {
+		return slug + ".md"
+	}
+	return fmt.Sprintf("%s/page-%d.md", slug, page)
+}
+
+// pageLinks returns the links to every page after the link to the first one
+// in the index, or nothing for a single page. dir is the directory of the
+// pages relative to the index.
+func pageLinks(dir, slug string, pages int) string {
+	if pages <= 1 {
+		return ""
+	}
+	links := make([]string, pages)
+	for i := range pages {
+		links[i] = fmt.Sprintf("[%d](%s%s)", i+1, dir, pathEscape(categoryPage(slug, i+1, pages)))
+	}
+	return " (pages " + strings.Join(links, ", ") + ")"
+}
+
+// pathEscape escapes each segment of a slash-separated path for a link.
+func pathEscape(path string) string {
+	segments := strings.Split(path, "/")
+	for i, s := range segments {
+		segments[i] = url.PathEscape(s)
+	}
+	return strings.Join(segments, "/")
+}
+
+// pageNavigation returns the links to the previous and next pages, or
+// nothing for a single page.
+func pageNavigation(page, pages int) string {
+	if pages <= 1 {
+		return ""
+	}
+	parts := make([]string, 0, 3)
+	if page > 1 {
+		parts = append(parts, fmt.Sprintf("[« Previous](./page-%d.md)", page-1))
+	}
+	parts = append(parts, fmt.Sprintf("Page %d of %d", page, pages))
+	if page < pages {
+		parts = append(parts, fmt.Sprintf("[Next »](./page-%d.md)", page+1))
+	}
+	return strings.Join(parts, " · ") + "\n\n"
+}
+
+// countShared returns the number of issues also listed in other categories.
+func countShared(issues []*client.Issue) int {
+	n := 0
+	for _, issue := range issues {
+		if len(issue.AlsoIn) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// issueColumns returns the columns of the issue tables enabled by c.
+func issueColumns(c *config.Config, now time.Time) []column {
+	columns := []column{
+		{"Repository", func(issue *client.Issue) string {
+			owner, repoName, _ := config.ParseRepoURL(issue.GetURL())
+			return fmt.Sprintf("[%s](https://github.com/%s/%s)", escapeText(repoName), owner, repoName)
+		}},
+		{"Title", func(issue *client.Issue) string {
+			return fmt.Sprintf("[%s](%s)", escapeText(issue.GetTitle()), issue.GetURL())
+		}},
+		{"UpdatedAt", func(issue *client.Issue) string {
+			return issue.GetUpdatedAt().Time.Format("2006-01-02")
+		}},
+		{"Labels", func(issue *client.Issue) string {
+			labels := make([]string, len(issue.Labels))
+			for i, label := range issue.Labels {
+				labels[i] = escapeText(label.GetName())
+			}
+			return strings.Join(labels, ", ")
+		}},
+		{"Assignee", func(issue *client.Issue) string {
+			if issue.Assignee.GetLogin() == "" {
+				return ""
+			}
+			return "@" + issue.Assignee.GetLogin()
+		}},
+		{"Comments", func(issue *client.Issue) string {
+			return strconv.Itoa(issue.GetComments())
+		}},
+		{"Reactions", func(issue *client.Issue) string {
+			total := issue.GetReactions().GetTotalCount()
+			if total == 0 {
+				return "0"
+			}
+			return fmt.Sprintf("%d (👍 %d)", total, issue.GetReactions().GetPlusOne())
+		}},
+	}
+
+	if c.CommentActivity.Enabled {
+		columns = append(columns,
+			column{"Last Commenter", func(issue *client.Issue) string {
+				if issue.Activity == nil || issue.Activity.LastCommenter == "" {
+					return ""
+				}
+				return "@" + issue.Activity.LastCommenter
+			}},
+			column{"Maintainer Reply", func(issue *client.Issue) string {
+				if issue.Activity == nil {
+					return ""
+				}
+				if !issue.Activity.MaintainerReplied {
+					return "no"
+				}
+				return since(issue.Activity.LastMaintainerReply, now)
+			}},
+			column{"Claimed By", func(issue *client.Issue) string {
+				if issue.Activity == nil || issue.Activity.Claim == nil {
+					return ""
+				}
+				if issue.Activity.Claim.Acknowledged {
+					return "@" + issue.Activity.Claim.Claimer + " (acknowledged)"
+				}
+				return "@" + issue.Activity.Claim.Claimer
+			}},
+		)
+	}
+	switch c.Excerpt.Mode {
+	case config.ExcerptColumn:
+		columns = append(columns, column{"Excerpt", func(issue *client.Issue) string {
+			return excerpt(issue.GetBody(), c.Excerpt.Length)
+		}})
+	case config.ExcerptDetails:
+		columns = append(columns, column{"Excerpt", func(issue *client.Issue) string {
+			text := excerpt(issue.GetBody(), c.Excerpt.Length)
+			if text == "" {
+				return ""
+			}
+			return "<details><summary>Show</summary>" + text + "</details>"
+		}})
+	}
+	if c.Scoring.Enabled {
+		columns = append(columns, column{"Score", func(issue *client.Issue) string {
+			return fmt.Sprintf("%.1f", issue.Score)
+		}})
+	}
+	if c.StaleAfter > 0 {
+		columns = append(columns, column{"Stale", func(issue *client.Issue) string {
+			if c.IsStale(issue.GetUpdatedAt().Time, now) {
+				return "stale"
+			}
+			return ""
+		}})
+	}
+	return columns
+}
+
+// since tells how long ago t was in days.
+func since(t, now time.Time) string {
+	days := int(now.Sub(t).Hours() / 24)
+	if days < 1 {
+		return "today"
+	}
+	return fmt.Sprintf("%dd ago", days)
+}
+
+// pullRequestColumns are appended to the columns of pull request tables.
+var pullRequestColumns = []column{
+	{"Draft", func(issue *client.Issue) string {
+		if issue.PullRequest != nil && issue.PullRequest.Draft {
+			return "draft"
+		}
+		return ""
+	}},
+	{"Review", func(issue *client.Issue) string {
+		if issue.PullRequest == nil {
+			return ""
+		}
+		return issue.PullRequest.ReviewStatus
+	}},
+	{"Mergeable", func(issue *client.Issue) string {
+		if issue.PullRequest == nil || issue.PullRequest.Mergeable == nil {
+			return ""
+		}
+		if *issue.PullRequest.Mergeable {
+			return "yes"
+		}
+		return "no"
+	}},
+}
+
+// splitPullRequests separates the pull requests from the issues, keeping
+// their order.
+func splitPullRequests(items []*client.Issue) (issues, prs []*client.Issue) {
+	for _, item := range items {
+		if item.IsPullRequest() {
+			prs = append(prs, item)
+		} else {
+			issues = append(issues, item)
+		}
+	}
+	return issues, prs
+}
+
+// availability describes the number of items of each searched type.
+func availability(c *config.Config, items []*client.Issue) string {
+	issues, prs := splitPullRequests(items)
+	switch {
+	case !c.HasItemType(config.ItemTypePullRequest):
+		return fmt.Sprintf("%d issues available", len(issues))
+	case !c.HasItemType(config.ItemTypeIssue):
+		return fmt.Sprintf("%d pull requests available", len(prs))
+	}
+	return fmt.Sprintf("%d issues and %d pull requests available", len(issues), len(prs))
+}
+
+// writeItems writes the table of issues, followed by the table of pull
+// requests when they are searched. level is the Markdown heading level of the
+// pull requests table, so that it nests under the heading of the page or of
+// the repository section the items belong to.
+func writeItems(sb *strings.Builder, c *config.Config, columns []column, items []*client.Issue, level int) {
+	issues, prs := splitPullRequests(items)
+	if c.HasItemType(config.ItemTypeIssue) {
+		writeTable(sb, columns, issues)
+		sb.WriteString("\n")
+	}
+	if c.HasItemType(config.ItemTypePullRequest) {
+		sb.WriteString(strings.Repeat("#", level) + " Pull Requests\n\n")
+		writeTable(sb, append(slices.Clip(columns), pullRequestColumns...), prs)
+		sb.WriteString("\n")
+	}
+}
+
+// writeGroupedItems writes a table of contents and a section per repository
+// with its own tables, without the Repository column.
+func writeGroupedItems(sb *strings.Builder, c *config.Config, columns []column, items []*client.Issue) {
+	columns = slices.DeleteFunc(slices.Clone(columns), func(col column) bool {
+		return col.header == "Repository"
+	})
+
+	var names []string
+	groups := make(map[string][]*client.Issue)
+	for _, issue := range items {
+		key := repoKey(issue)
+		if _, ok := groups[key]; !ok {
+			names = append(names, key)
+		}
+		groups[key] = append(groups[key], issue)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+
+	sb.WriteString("## Contents\n\n")
+	for _, name := range names {
+		sb.WriteString(fmt.Sprintf("- [%s](#%s) - %s\n", escapeText(name), anchor(name), availability(c, groups[name])))
+	}
+	sb.WriteString("\n")
+
+	for _, name := range names {
+		group := groups[name]
+		sb.WriteString(fmt.Sprintf("### %s\n\n", escapeText(name)))
+		info := []string{fmt.Sprintf("[Repository](https://github.com/%s)", name)}
+		if repo := group[0].Repo; repo != nil {
+			if repo.Description != "" {
+				sb.WriteString(escapeText(repo.Description) + "\n\n")
+			}
+			info = append(info, fmt.Sprintf("%d stars", repo.Stars))
+		}
+		info = append(info, availability(c, group))
+		sb.WriteString(strings.Join(info, " · ") + "\n\n")
+		writeItems(sb, c, columns, group, 4)
+	}
+}
+
+// anchor returns the fragment GitHub generates for a heading: lower case,
+// spaces replaced by hyphens and punctuation other than hyphens and
+// underscores removed.
+func anchor(heading string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('-')
+		}
+	}
+	return b.String()
+}
+
+func writeTable(sb *strings.Builder, columns []column, issues []*client.Issue) {
+	headers := make([]string, len(columns))
+	separators := make([]string, len(columns))
+	for i, col := range columns {
+		headers[i] = col.header
+		separators[i] = "---"
+	}
+	sb.WriteString("| " + strings.Join(headers, " | ") + " |\n")
+	sb.WriteString("| " + strings.Join(separators, " | ") + " |\n")
+
+	cells := make([]string, len(columns))
+	for _, issue := range issues {
+		for i, col := range columns {
+			cells[i] = col.value(issue)
+		}
+		sb.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+}
+
+func writeRepoSummary(sb *strings.Builder, repos []*client.CategoryRepository) {
+	sb.WriteString("## Repositories\n\n")
+	sb.WriteString("| Repository | Stars | Language | License | Last Push | Open Issues | CONTRIBUTING | Status |\n")
+	sb.WriteString("| --- | --- | --- | --- | --- | --- | --- | --- |\n")
+	for _, r := range repos {
+		var pushedAt, contributing string
+		if !r.PushedAt.IsZero() {
+			pushedAt = r.PushedAt.Format("2006-01-02")
+		}
+		if r.HasContributing {
+			contributing = "yes"
+		}
+		var status string
+		switch {
+		case r.SkipReason != "":
+			status = "skipped: " + r.SkipReason
+		case r.Archived:
+			status = "archived"
+		}
+		sb.WriteString(fmt.Sprintf(
+			"| [%s](%s) | %d | %s | %s | %s | %d | %s | %s |\n",
+			escapeText(r.FullName()),
+			r.URL(),
+			r.Stars,
+			escapeText(r.Language),
+			escapeText(r.License),
+			pushedAt,
+			r.OpenIssues,
+			contributing,
+			status,
+		))
+	}
+	sb.WriteString("\n")
+}
+
+type markdownFile struct {
+	pathRelative string
+	content      string
+}
+
+type markdownFiles []markdownFile
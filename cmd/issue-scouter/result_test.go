@@ -0,0 +1,1089 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/claims"
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func TestGenerateMarkdown(t *testing.T) {
+	fixedTime := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		config *config.Config
+		issues client.Issues
+		repos  client.Repositories
+		want   markdownFiles
+	}{
+		{
+			name: "generates markdown files correctly",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 1"),
+						HTMLURL:   github.Ptr("https://github.com/owner/repo/issues/1"),
+						UpdatedAt: &github.Timestamp{Time: fixedTime},
+						URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+						Labels: []*github.Label{
+							{Name: github.Ptr("bug")},
+						},
+						Assignee: &github.User{Login: github.Ptr("user1"), Name: github.Ptr("user1")},
+						Comments: github.Ptr(2),
+					}},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 | bug | @user1 | 2 | 0 |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 1 issues available](./issues/team-a.md)\n",
+				},
+			},
+		},
+		{
+			name: "uses slugs of category names as file names",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+			},
+			issues: client.Issues{
+				"../x": []*client.Issue{
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 1"),
+						HTMLURL:   github.Ptr("https://github.com/owner/repo/issues/1"),
+						UpdatedAt: &github.Timestamp{Time: fixedTime},
+						URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+					}},
+				},
+				"Web / Frontend": []*client.Issue{
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 1"),
+						HTMLURL:   github.Ptr("https://github.com/owner/repo/issues/1"),
+						UpdatedAt: &github.Timestamp{Time: fixedTime},
+						URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+					}},
+				},
+				"日本語": []*client.Issue{
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 1"),
+						HTMLURL:   github.Ptr("https://github.com/owner/repo/issues/1"),
+						UpdatedAt: &github.Timestamp{Time: fixedTime},
+						URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+					}},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/x.md",
+					content: "# ../x\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 |\n\n",
+				},
+				{
+					pathRelative: "output/issues/Web-Frontend.md",
+					content: "# Web / Frontend\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 |\n\n",
+				},
+				{
+					pathRelative: "output/issues/日本語.md",
+					content: "# 日本語\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [../x - 1 issues available](./issues/x.md)\n" +
+						"- [Web / Frontend - 1 issues available](./issues/Web-Frontend.md)\n" +
+						"- [日本語 - 1 issues available](./issues/%E6%97%A5%E6%9C%AC%E8%AA%9E.md)\n" +
+						"\n1 unique issues in total\n",
+				},
+			},
+		},
+		{
+			name: "links the statistics from the index",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+				Stats:       config.Stats{Enabled: true, Weeks: 12},
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 1"),
+						HTMLURL:   github.Ptr("https://github.com/owner/repo/issues/1"),
+						UpdatedAt: &github.Timestamp{Time: fixedTime},
+						URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+					}},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 1 issues available](./issues/team-a.md)\n" +
+						"\n## Statistics\n\n" +
+						"- [Trends of the last 12 weeks](./STATS.md)\n",
+				},
+			},
+		},
+		{
+			name: "generates markdown files with metadata",
+			config: &config.Config{
+				Destination:     "output",
+				Description:     "Test description",
+				IncludeMetadata: true,
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 1"),
+						Body:      github.Ptr("Issue description"),
+						HTMLURL:   github.Ptr("https://github.com/owner/repo/issues/1"),
+						UpdatedAt: &github.Timestamp{Time: fixedTime},
+						URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+						Labels: []*github.Label{
+							{
+								Name:        github.Ptr("bug"),
+								Color:       github.Ptr("red"),
+								Description: github.Ptr("Bug report"),
+							},
+						},
+						Assignee: &github.User{
+							Login: github.Ptr("user1"),
+							Name:  github.Ptr("User One"),
+							Email: github.Ptr("user1@example.com"),
+						},
+						Comments:  github.Ptr(2),
+						Reactions: &github.Reactions{TotalCount: github.Ptr(5), PlusOne: github.Ptr(3)},
+					}},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 | bug | @user1 | 2 | 5 (👍 3) |\n\n" +
+						"\n<!--\n" +
+						`{
+  "title": "Issue 1",
+  "body": "Issue description",
+  "labels": [
+    {
+      "name": "bug",
+      "color": "red",
+      "description": "Bug report"
+    }
+  ],
+  "assignee": {
+    "login": "user1",
+    "name": "User One",
+    "email": "user1@example.com"
+  },
+  "comments": 2,
+  "reactions": {
+    "total": 5,
+    "+1": 3
+  },
+  "updated_at": "2025-03-09T10:00:00Z",
+  "url": "https://github.com/owner/repo/issues/1"
+}` +
+						"\n-->\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 1 issues available](./issues/team-a.md)\n",
+				},
+			},
+		},
+		{
+			name: "generates markdown files with score",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+				Scoring: config.Scoring{
+					Enabled:  true,
+					TopPicks: 1,
+				},
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 1"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+							Comments:  github.Ptr(0),
+						},
+						Score: 87.5,
+					},
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 2"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/repo/issues/2"),
+							Comments:  github.Ptr(1),
+						},
+						Score: 40,
+					},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions | Score |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 | 87.5 |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 2](https://github.com/owner/repo/issues/2) | 2025-03-09 |  |  | 1 | 0 | 40.0 |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 2 issues available](./issues/team-a.md)\n" +
+						"\n## Top Picks\n\n" +
+						"| Score | Category | Repository | Title |\n" +
+						"| --- | --- | --- | --- |\n" +
+						"| 87.5 | [team-a](./issues/team-a.md) | [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) |\n",
+				},
+			},
+		},
+		{
+			name: "generates markdown files with stale column",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+				StaleAfter:  config.Duration(180 * config.Day),
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 1"),
+						UpdatedAt: &github.Timestamp{Time: time.Now()},
+						URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+					}},
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 2"),
+						UpdatedAt: &github.Timestamp{Time: time.Now().AddDate(-1, 0, 0)},
+						URL:       github.Ptr("https://github.com/owner/repo/issues/2"),
+					}},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions | Stale |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+						fmt.Sprintf("| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | %s |  |  | 0 | 0 |  |\n", time.Now().Format("2006-01-02")) +
+						fmt.Sprintf("| [repo](https://github.com/owner/repo) | [Issue 2](https://github.com/owner/repo/issues/2) | %s |  |  | 0 | 0 | stale |\n\n", time.Now().AddDate(-1, 0, 0).Format("2006-01-02")),
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 2 issues available](./issues/team-a.md)\n",
+				},
+			},
+		},
+		{
+			name: "generates markdown files with repository summary",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+				RepoSummary: true,
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{},
+			},
+			repos: client.Repositories{
+				"team-a": {
+					{Repository: &client.Repository{
+						Owner:           "owner",
+						Name:            "repo",
+						Stars:           120,
+						PushedAt:        fixedTime,
+						OpenIssues:      7,
+						Language:        "Go",
+						License:         "MIT",
+						HasContributing: true,
+					}},
+					{
+						Repository: &client.Repository{Owner: "owner", Name: "old", Archived: true},
+						SkipReason: "archived",
+					},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n\n" +
+						"## Repositories\n\n" +
+						"| Repository | Stars | Language | License | Last Push | Open Issues | CONTRIBUTING | Status |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [owner/repo](https://github.com/owner/repo) | 120 | Go | MIT | 2025-03-09 | 7 | yes |  |\n" +
+						"| [owner/old](https://github.com/owner/old) | 0 |  |  |  | 0 |  | skipped: archived |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 0 issues available](./issues/team-a.md)\n",
+				},
+			},
+		},
+		{
+			name: "generates markdown files by language",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+				Views:       []string{config.ViewCategory, config.ViewLanguage},
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 1"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+						},
+						Repo: &client.Repository{Owner: "owner", Name: "repo", Language: "Go"},
+					},
+				},
+				"team-b": []*client.Issue{
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 1"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+						},
+						Repo: &client.Repository{Owner: "owner", Name: "repo", Language: "Go"},
+					},
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 2"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/docs/issues/2"),
+						},
+						Repo: &client.Repository{Owner: "owner", Name: "docs"},
+					},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 |\n\n",
+				},
+				{
+					pathRelative: "output/issues/team-b.md",
+					content: "# team-b\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 |\n" +
+						"| [docs](https://github.com/owner/docs) | [Issue 2](https://github.com/owner/docs/issues/2) | 2025-03-09 |  |  | 0 | 0 |\n\n",
+				},
+				{
+					pathRelative: "output/issues/languages/Go.md",
+					content: "# Go\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 |\n\n",
+				},
+				{
+					pathRelative: "output/issues/languages/Unknown.md",
+					content: "# Unknown\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [docs](https://github.com/owner/docs) | [Issue 2](https://github.com/owner/docs/issues/2) | 2025-03-09 |  |  | 0 | 0 |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 1 issues available](./issues/team-a.md)\n" +
+						"- [team-b - 2 issues available](./issues/team-b.md)\n" +
+						"\n2 unique issues in total\n" +
+						"\n## Languages\n\n" +
+						"- [Go - 1 issues available](./issues/languages/Go.md)\n" +
+						"- [Unknown - 1 issues available](./issues/languages/Unknown.md)\n",
+				},
+			},
+		},
+		{
+			name: "generates markdown files with cross-referenced duplicates",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+				Duplicates:  config.DuplicatesCrossReference,
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 1"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+						},
+						AlsoIn: []string{"team-b"},
+					},
+				},
+				"team-b": []*client.Issue{
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 1"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+						},
+						AlsoIn: []string{"team-a"},
+					},
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 2"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/repo/issues/2"),
+						},
+					},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions | Also In |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 | [team-b](./team-b.md) |\n\n",
+				},
+				{
+					pathRelative: "output/issues/team-b.md",
+					content: "# team-b\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions | Also In |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 | [team-a](./team-a.md) |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 2](https://github.com/owner/repo/issues/2) | 2025-03-09 |  |  | 0 | 0 |  |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 1 issues available, 1 also in other categories](./issues/team-a.md)\n" +
+						"- [team-b - 2 issues available, 1 also in other categories](./issues/team-b.md)\n" +
+						"\n2 unique issues in total\n",
+				},
+			},
+		},
+		{
+			name: "generates markdown files with pull requests",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+				ItemTypes:   []string{config.ItemTypeIssue, config.ItemTypePullRequest},
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 1"),
+						UpdatedAt: &github.Timestamp{Time: fixedTime},
+						URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+					}},
+					{
+						Issue: &github.Issue{
+							Title:            github.Ptr("PR 2"),
+							UpdatedAt:        &github.Timestamp{Time: fixedTime},
+							URL:              github.Ptr("https://github.com/owner/repo/issues/2"),
+							PullRequestLinks: &github.PullRequestLinks{},
+						},
+						PullRequest: &client.PullRequest{
+							Draft:        true,
+							Mergeable:    github.Ptr(true),
+							ReviewStatus: client.ReviewChangesRequested,
+						},
+					},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 |\n\n" +
+						"## Pull Requests\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions | Draft | Review | Mergeable |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [PR 2](https://github.com/owner/repo/issues/2) | 2025-03-09 |  |  | 0 | 0 | draft | changes requested | yes |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 1 issues and 1 pull requests available](./issues/team-a.md)\n",
+				},
+			},
+		},
+		{
+			name: "generates markdown files with excerpts",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+				Excerpt:     config.Excerpt{Mode: config.ExcerptDetails, Length: 50},
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 1"),
+						Body:      github.Ptr("## Bug\n\nThe **CLI** crashes, see ![log](https://example.com/log.png)"),
+						UpdatedAt: &github.Timestamp{Time: fixedTime},
+						URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+					}},
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 2"),
+						UpdatedAt: &github.Timestamp{Time: fixedTime},
+						URL:       github.Ptr("https://github.com/owner/repo/issues/2"),
+					}},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions | Excerpt |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 | <details><summary>Show</summary>Bug The CLI crashes, see</details> |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 2](https://github.com/owner/repo/issues/2) | 2025-03-09 |  |  | 0 | 0 |  |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 2 issues available](./issues/team-a.md)\n",
+				},
+			},
+		},
+		{
+			name: "generates markdown files with comment activity",
+			config: &config.Config{
+				Destination:     "output",
+				Description:     "Test description",
+				CommentActivity: config.CommentActivity{Enabled: true},
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 1"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+							Comments:  github.Ptr(2),
+						},
+						Activity: &client.Activity{
+							LastCommenter:       "user1",
+							MaintainerReplied:   true,
+							LastMaintainerReply: time.Now().Add(-73 * time.Hour),
+							Claim:               &claims.Claim{Claimer: "user2", Acknowledged: true},
+						},
+					},
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 2"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/repo/issues/2"),
+						},
+						Activity: &client.Activity{},
+					},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions | Last Commenter | Maintainer Reply | Claimed By |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 2 | 0 | @user1 | 3d ago | @user2 (acknowledged) |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 2](https://github.com/owner/repo/issues/2) | 2025-03-09 |  |  | 0 | 0 |  | no |  |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 2 issues available](./issues/team-a.md)\n",
+				},
+			},
+		},
+		{
+			name: "generates markdown files grouped by repository",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+				Layout:      config.LayoutGrouped,
+			},
+			issues: client.Issues{
+				"team-a": []*client.Issue{
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 1"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/repo_b/issues/1"),
+						},
+					},
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 2"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/Owner/Repo.A/issues/2"),
+						},
+						Repo: &client.Repository{Owner: "Owner", Name: "Repo.A", Description: "A *fast* library", Stars: 42},
+					},
+					{
+						Issue: &github.Issue{
+							Title:     github.Ptr("Issue 3"),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr("https://github.com/owner/repo_b/issues/3"),
+						},
+					},
+				},
+			},
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a.md",
+					content: "# team-a\n\n" +
+						"## Contents\n\n" +
+						"- [Owner/Repo.A](#ownerrepoa) - 1 issues available\n" +
+						"- [owner/repo\\_b](#ownerrepo_b) - 2 issues available\n\n" +
+						"### Owner/Repo.A\n\n" +
+						"A \\*fast\\* library\n\n" +
+						"[Repository](https://github.com/Owner/Repo.A) · 42 stars · 1 issues available\n\n" +
+						"| Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- |\n" +
+						"| [Issue 2](https://github.com/Owner/Repo.A/issues/2) | 2025-03-09 |  |  | 0 | 0 |\n\n" +
+						"### owner/repo\\_b\n\n" +
+						"[Repository](https://github.com/owner/repo_b) · 2 issues available\n\n" +
+						"| Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- |\n" +
+						"| [Issue 1](https://github.com/owner/repo_b/issues/1) | 2025-03-09 |  |  | 0 | 0 |\n" +
+						"| [Issue 3](https://github.com/owner/repo_b/issues/3) | 2025-03-09 |  |  | 0 | 0 |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 3 issues available](./issues/team-a.md)\n",
+				},
+			},
+		},
+		{
+			name: "generates paginated category pages",
+			config: &config.Config{
+				Destination:      "output",
+				Description:      "Test description",
+				MaxIssuesPerPage: 2,
+				Duplicates:       config.DuplicatesCrossReference,
+			},
+			issues: func() client.Issues {
+				newIssue := func(n int, alsoIn ...string) *client.Issue {
+					return &client.Issue{
+						Issue: &github.Issue{
+							Title:     github.Ptr(fmt.Sprintf("Issue %d", n)),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr(fmt.Sprintf("https://github.com/owner/repo/issues/%d", n)),
+						},
+						AlsoIn: alsoIn,
+					}
+				}
+				return client.Issues{
+					"team-a": {newIssue(1), newIssue(2), newIssue(3, "team-b")},
+					"team-b": {newIssue(3, "team-a")},
+				}
+			}(),
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/team-a/page-1.md",
+					content: "# team-a\n\n" +
+						"Page 1 of 2 · [Next »](./page-2.md)\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions | Also In |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 |  |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 2](https://github.com/owner/repo/issues/2) | 2025-03-09 |  |  | 0 | 0 |  |\n\n" +
+						"Page 1 of 2 · [Next »](./page-2.md)\n\n",
+				},
+				{
+					pathRelative: "output/issues/team-a/page-2.md",
+					content: "# team-a\n\n" +
+						"[« Previous](./page-1.md) · Page 2 of 2\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions | Also In |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 3](https://github.com/owner/repo/issues/3) | 2025-03-09 |  |  | 0 | 0 | [team-b](../team-b.md) |\n\n" +
+						"[« Previous](./page-1.md) · Page 2 of 2\n\n",
+				},
+				{
+					pathRelative: "output/issues/team-b.md",
+					content: "# team-b\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions | Also In |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 3](https://github.com/owner/repo/issues/3) | 2025-03-09 |  |  | 0 | 0 | [team-a](./team-a/page-1.md) |\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n" +
+						"- [team-a - 3 issues available, 1 also in other categories](./issues/team-a/page-1.md) (pages [1](./issues/team-a/page-1.md), [2](./issues/team-a/page-2.md))\n" +
+						"- [team-b - 1 issues available, 1 also in other categories](./issues/team-b.md)\n" +
+						"\n3 unique issues in total\n",
+				},
+			},
+		},
+		{
+			name: "generates paginated language pages",
+			config: &config.Config{
+				Destination:      "output",
+				Description:      "Test description",
+				MaxIssuesPerPage: 2,
+				Views:            []string{config.ViewLanguage},
+			},
+			issues: func() client.Issues {
+				newIssue := func(n int) *client.Issue {
+					return &client.Issue{
+						Issue: &github.Issue{
+							Title:     github.Ptr(fmt.Sprintf("Issue %d", n)),
+							UpdatedAt: &github.Timestamp{Time: fixedTime},
+							URL:       github.Ptr(fmt.Sprintf("https://github.com/owner/repo/issues/%d", n)),
+						},
+						Repo: &client.Repository{Owner: "owner", Name: "repo", Language: "Go"},
+					}
+				}
+				return client.Issues{
+					"team-a": {newIssue(1), newIssue(2), newIssue(3)},
+				}
+			}(),
+			want: markdownFiles{
+				{
+					pathRelative: "output/issues/languages/Go/page-1.md",
+					content: "# Go\n\n" +
+						"Page 1 of 2 · [Next »](./page-2.md)\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 1](https://github.com/owner/repo/issues/1) | 2025-03-09 |  |  | 0 | 0 |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 2](https://github.com/owner/repo/issues/2) | 2025-03-09 |  |  | 0 | 0 |\n\n" +
+						"Page 1 of 2 · [Next »](./page-2.md)\n\n",
+				},
+				{
+					pathRelative: "output/issues/languages/Go/page-2.md",
+					content: "# Go\n\n" +
+						"[« Previous](./page-1.md) · Page 2 of 2\n\n" +
+						"| Repository | Title | UpdatedAt | Labels | Assignee | Comments | Reactions |\n" +
+						"| --- | --- | --- | --- | --- | --- | --- |\n" +
+						"| [repo](https://github.com/owner/repo) | [Issue 3](https://github.com/owner/repo/issues/3) | 2025-03-09 |  |  | 0 | 0 |\n\n" +
+						"[« Previous](./page-1.md) · Page 2 of 2\n\n",
+				},
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Languages\n\n" +
+						"- [Go - 3 issues available](./issues/languages/Go/page-1.md) (pages [1](./issues/languages/Go/page-1.md), [2](./issues/languages/Go/page-2.md))\n",
+				},
+			},
+		},
+		{
+			name: "handles empty issues",
+			config: &config.Config{
+				Destination: "output",
+				Description: "Test description",
+			},
+			issues: client.Issues{},
+			want: markdownFiles{
+				{
+					pathRelative: "output/README.md",
+					content: "# Issue List\n\n" +
+						fmt.Sprintf("Last Updated: %s\n", time.Now().Format("2006-01-02 15:04:05")) +
+						"\nTest description\n\n" +
+						"## Index\n\n",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateMarkdown(tt.config, tt.issues, tt.repos)
+			assert.Equal(t, len(tt.want), len(got))
+
+			for i := range got {
+				assert.Equal(t, tt.want[i].pathRelative, got[i].pathRelative)
+				assert.Equal(t, tt.want[i].content, got[i].content)
+			}
+		})
+	}
+}
+
+func TestSaveToFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		dest    string
+		files   markdownFiles
+		wantErr bool
+	}{
+		{
+			name: "saves files successfully",
+			dest: "output",
+			files: markdownFiles{
+				{
+					pathRelative: "output/file1.md",
+					content:      "test content 1",
+				},
+				{
+					pathRelative: "output/subdir/file2.md",
+					content:      "test content 2",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "refuses a destination outside of the working directory",
+			dest: "../output",
+			files: markdownFiles{
+				{pathRelative: "../output/file1.md", content: "test content 1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "refuses an absolute destination",
+			dest: "/tmp/output",
+			files: markdownFiles{
+				{pathRelative: "/tmp/output/file1.md", content: "test content 1"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "refuses files outside of the destination",
+			dest: "output",
+			files: markdownFiles{
+				{pathRelative: "other/file1.md", content: "test content 1"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+
+			err := tt.files.saveToFiles(&config.Config{
+				Destination: tt.dest,
+				Description: "Test description",
+			})
+			if tt.wantErr {
+				assert.Error(t, err)
+				for _, f := range tt.files {
+					assert.NoFileExists(t, f.pathRelative)
+				}
+				return
+			}
+
+			assert.NoError(t, err)
+
+			// Verify files were created with correct content
+			for _, f := range tt.files {
+				content, err := os.ReadFile(f.pathRelative)
+				assert.NoError(t, err)
+				assert.Equal(t, f.content, string(content))
+			}
+			manifest, err := os.ReadFile(filepath.Join(tt.dest, manifestName))
+			assert.NoError(t, err)
+			assert.JSONEq(t, `{"files": ["file1.md", "subdir/file2.md"]}`, string(manifest))
+			entries, err := os.ReadDir(tt.dest)
+			assert.NoError(t, err)
+			assert.Len(t, entries, 3, "the staging directory should be removed")
+		})
+	}
+}
+
+func TestSaveToFilesRefusesSymlinkOutsideWorkspace(t *testing.T) {
+	outside := t.TempDir()
+	t.Chdir(t.TempDir())
+	assert.NoError(t, os.Symlink(outside, "output"))
+
+	files := markdownFiles{{pathRelative: "output/README.md", content: "new"}}
+	assert.Error(t, files.saveToFiles(&config.Config{Destination: "output"}))
+	assert.NoFileExists(t, filepath.Join(outside, "README.md"))
+}
+
+func TestSaveToFilesRemovesStalePages(t *testing.T) {
+	t.Chdir(t.TempDir())
+	issuesDir := filepath.Join("output", "issues")
+	assert.NoError(t, os.MkdirAll(filepath.Join(issuesDir, "team-a"), 0750))
+	// Without a manifest, all the files of the issues directory were
+	// generated by an older version
+	for _, name := range []string{"team-a/page-1.md", "team-a/page-2.md", "team-a/page-3.md", "team-b.md"} {
+		assert.NoError(t, os.WriteFile(filepath.Join(issuesDir, name), []byte("old"), 0640))
+	}
+
+	files := markdownFiles{
+		{pathRelative: filepath.Join(issuesDir, "team-a.md"), content: "new"},
+		{pathRelative: filepath.Join(issuesDir, "team-b", "page-1.md"), content: "new"},
+		{pathRelative: filepath.Join(issuesDir, "team-b", "page-2.md"), content: "new"},
+	}
+	assert.NoError(t, files.saveToFiles(&config.Config{Destination: "output"}))
+	assert.Equal(t, []string{"team-a.md", "team-b/page-1.md", "team-b/page-2.md"}, listFiles(t, issuesDir))
+	assert.NoDirExists(t, filepath.Join(issuesDir, "team-a"))
+}
+
+func TestSaveToFilesKeepsOtherFiles(t *testing.T) {
+	t.Chdir(t.TempDir())
+	issuesDir := filepath.Join("output", "issues")
+
+	first := markdownFiles{
+		{pathRelative: filepath.Join("output", "README.md"), content: "first"},
+		{pathRelative: filepath.Join(issuesDir, "team-a.md"), content: "first"},
+		{pathRelative: filepath.Join(issuesDir, "team-b.md"), content: "first"},
+	}
+	assert.NoError(t, first.saveToFiles(&config.Config{Destination: "output"}))
+	assert.NoError(t, os.WriteFile(filepath.Join(issuesDir, "notes.md"), []byte("mine"), 0640))
+
+	second := markdownFiles{
+		{pathRelative: filepath.Join("output", "README.md"), content: "second"},
+		{pathRelative: filepath.Join(issuesDir, "team-a.md"), content: "second"},
+	}
+	assert.NoError(t, second.saveToFiles(&config.Config{Destination: "output"}))
+	assert.Equal(t, []string{"notes.md", "team-a.md"}, listFiles(t, issuesDir))
+	content, err := os.ReadFile(filepath.Join(issuesDir, "team-a.md"))
+	assert.NoError(t, err)
+	assert.Equal(t, "second", string(content))
+}
+
+func TestSaveToFilesRecoversInterruptedRun(t *testing.T) {
+	tests := []struct {
+		name         string
+		journal      bool
+		want         []string
+		wantManifest []string
+	}{
+		{
+			name:         "completes a staging directory with a journal",
+			journal:      true,
+			want:         []string{manifestName, "README.md", "issues/team-c.md"},
+			wantManifest: []string{"README.md", "issues/team-c.md"},
+		},
+		{
+			name:         "discards a staging directory without a journal",
+			journal:      false,
+			want:         []string{manifestName, "README.md", "issues/team-a.md"},
+			wantManifest: []string{"README.md", "issues/team-a.md"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			first := markdownFiles{
+				{pathRelative: filepath.Join("output", "README.md"), content: "first"},
+				{pathRelative: filepath.Join("output", "issues", "team-a.md"), content: "first"},
+			}
+			assert.NoError(t, first.saveToFiles(&config.Config{Destination: "output"}))
+
+			// A run interrupted after staging a file
+			staging := filepath.Join("output", stagingName)
+			assert.NoError(t, os.MkdirAll(filepath.Join(staging, "issues"), 0750))
+			assert.NoError(t, os.WriteFile(filepath.Join(staging, "issues", "team-c.md"), []byte("interrupted"), 0640))
+			if tt.journal {
+				assert.NoError(t, writeJSON(staging, manifestName, manifest{
+					Files:  []string{"README.md", "issues/team-c.md"},
+					Remove: []string{"issues/team-a.md"},
+				}))
+			}
+
+			assert.NoError(t, recoverStaging("output"))
+			assert.Equal(t, tt.want, listFiles(t, "output"))
+			assert.NoDirExists(t, staging)
+			previous, err := previousFiles("output")
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantManifest, previous)
+		})
+	}
+}
+
+// listFiles returns the slash-separated paths of the files under dir.
+func listFiles(t *testing.T, dir string) []string {
+	t.Helper()
+	var got []string
+	err := filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			got = append(got, filepath.ToSlash(rel))
+		}
+		return err
+	})
+	assert.NoError(t, err)
+	return got
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+	"github.com/ymtdzzz/issue-scouter/pkg/history"
+)
+
+func runCommand(args []string) error {
+	fs := newFlagSet("run", "[flags]")
+	configFile := configFlag(fs)
+	dest := destFlag(fs)
+	token := tokenFlag(fs)
+	// Not read from INPUT_DRY_RUN: the dry_run input of the action writes
+	// and commits the files, and only skips pushing them
+	dryRun := fs.Bool("dry-run", false, "Print the files that would be written instead of writing them")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	co, err := loadConfig(configFile.files, *dest)
+	if err != nil {
+		return err
+	}
+
+	startedAt := time.Now()
+	c := client.NewClient(co, *token)
+	issues, err := c.FetchIssues()
+	if err != nil {
+		return fmt.Errorf("failed to fetch issues: %w", err)
+	}
+
+	var db *history.DB
+	if co.SQLite.Enabled && !*dryRun {
+		if db, err = openHistory(co); err != nil {
+			return fmt.Errorf("failed to export to SQLite: %w", err)
+		}
+		defer db.Close()
+	}
+
+	files, run, err := buildFiles(co, issues, c.Repositories(), startedAt, db)
+	if err != nil {
+		return err
+	}
+	if run != nil {
+		// Does nothing once committed
+		defer run.Rollback()
+	}
+	if *dryRun {
+		log.Printf("Dry-run mode: skip writing %d files", len(files))
+		for _, f := range files {
+			fmt.Printf("%s (%d bytes)\n", f.pathRelative, len(f.content))
+		}
+		return nil
+	}
+
+	if err := files.saveToFiles(co); err != nil {
+		return fmt.Errorf("failed to save Markdown file: %w", err)
+	}
+	// Only runs which published their list are part of the history
+	if run != nil {
+		if err := run.Commit(); err != nil {
+			return fmt.Errorf("failed to export to SQLite: %w", err)
+		}
+		log.Printf("Recorded run %d in %s", run.ID, filepath.Join(co.Destination, co.SQLite.Path))
+	}
+	return nil
+}
+
+// buildFiles ranks the issues and generates every file of the list, as
+// written by run and compared by diff. With db, the run is recorded in a
+// transaction before the statistics are computed, so that they include it,
+// and returned to be committed once the files are saved.
+func buildFiles(co *config.Config, issues client.Issues, repos client.Repositories, now time.Time, db *history.DB) (files markdownFiles, run *history.Run, err error) {
+	dedupeIssues(co.Duplicates, issues)
+	scoreIssues(co, issues, now)
+	sortIssues(co, issues)
+
+	if db != nil {
+		if run, err = db.Begin(co, now, issues, repos); err != nil {
+			return nil, nil, fmt.Errorf("failed to export to SQLite: %w", err)
+		}
+		defer func() {
+			if err != nil {
+				run.Rollback()
+			}
+		}()
+	}
+
+	files = generateMarkdown(co, issues, repos)
+	if co.LLM.Enabled {
+		export, err := generateLLMExport(co, issues)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to generate LLM export: %w", err)
+		}
+		files = append(files, export...)
+	}
+	if co.Stats.Enabled {
+		var trends *history.Trends
+		if run != nil {
+			trends, err = run.Trends(now, co.Stats.Weeks)
+		} else {
+			trends, err = loadTrends(co, now)
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to compute statistics: %w", err)
+		}
+		files = append(files, generateStats(co, trends, now))
+	}
+	return files, run, nil
+}
+
+// openHistory opens the SQLite database, creating it if needed.
+func openHistory(co *config.Config) (*history.DB, error) {
+	path := filepath.Join(co.Destination, co.SQLite.Path)
+	if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+		return nil, fmt.Errorf("failed to create directory %s: %v", filepath.Dir(path), err)
+	}
+	return history.Open(path)
+}
+
+func loadConfig(configFiles []string, dest string) (*config.Config, error) {
+	if len(configFiles) == 0 {
+		return nil, errors.New("no config file specified, use --config or INPUT_CONFIG_FILE")
+	}
+
+	co, err := config.LoadConfig(configFiles...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load config: %w", err)
+	}
+	// Validate the destination given on the command line too
+	if dest != "" {
+		co.Destination = dest
+	}
+	if err := co.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config, run 'issue-scouter validate' for details:\n%w", err)
+	}
+	return co, nil
+}
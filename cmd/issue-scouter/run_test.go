@@ -0,0 +1,91 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+	"github.com/ymtdzzz/issue-scouter/pkg/history"
+)
+
+func TestBuildFiles(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		record    bool
+		wantStats string
+	}{
+		{
+			name:      "records the run in the statistics before committing it",
+			record:    true,
+			wantStats: "from 1 runs",
+		},
+		{
+			name:      "leaves the history untouched without record",
+			record:    false,
+			wantStats: "No runs recorded yet.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Chdir(t.TempDir())
+			co := &config.Config{
+				Destination: "output",
+				SQLite:      config.SQLite{Enabled: true, Path: "history.db"},
+				Stats:       config.Stats{Enabled: true, Weeks: 2, TopRepositories: 1},
+			}
+			issues := client.Issues{
+				"team-a": []*client.Issue{
+					{Issue: &github.Issue{
+						Title:     github.Ptr("Issue 1"),
+						HTMLURL:   github.Ptr("https://github.com/owner/repo/issues/1"),
+						URL:       github.Ptr("https://github.com/owner/repo/issues/1"),
+						CreatedAt: &github.Timestamp{Time: now.AddDate(0, 0, -3)},
+						UpdatedAt: &github.Timestamp{Time: now},
+					}},
+				},
+			}
+
+			var db *history.DB
+			if tt.record {
+				var err error
+				db, err = openHistory(co)
+				assert.NoError(t, err)
+				defer db.Close()
+			}
+
+			files, run, err := buildFiles(co, issues, client.Repositories{}, now, db)
+			assert.NoError(t, err)
+			var paths []string
+			for _, f := range files {
+				paths = append(paths, f.pathRelative)
+			}
+			assert.Equal(t, []string{"output/issues/team-a.md", "output/README.md", "output/STATS.md"}, paths)
+			assert.Contains(t, files[2].content, tt.wantStats)
+			if !tt.record {
+				assert.Nil(t, run)
+				assert.NoFileExists(t, filepath.Join("output", "history.db"))
+				return
+			}
+
+			// The run is only visible to others once committed
+			runs := func() int {
+				t.Helper()
+				other, err := history.OpenReadOnly(filepath.Join("output", "history.db"))
+				assert.NoError(t, err)
+				defer other.Close()
+				trends, err := other.Trends(now, 2)
+				assert.NoError(t, err)
+				return trends.Runs
+			}
+			assert.Equal(t, 0, runs())
+			assert.NoError(t, run.Commit())
+			assert.Equal(t, 1, runs())
+		})
+	}
+}
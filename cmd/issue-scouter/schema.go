@@ -0,0 +1,21 @@
+package main
+
+import (
+	"os"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func schemaCommand(args []string) error {
+	fs := newFlagSet("schema", "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	data, err := config.JSONSchema()
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+	"github.com/ymtdzzz/issue-scouter/pkg/score"
+)
+
+// activityWindow is the period in which an issue counts as recently updated
+// for the repository activity signal.
+const activityWindow = 30 * 24 * time.Hour
+
+// scoreIssues sets the score of every issue. sortIssues ranks the categories
+// by it.
+func scoreIssues(c *config.Config, issues client.Issues, now time.Time) {
+	if !c.Scoring.Enabled {
+		return
+	}
+
+	activity := repoActivity(issues, now)
+	s := score.New(c.Scoring, now)
+	for _, is := range issues {
+		for _, issue := range is {
+			labels := make([]string, len(issue.Labels))
+			for i, l := range issue.Labels {
+				labels[i] = l.GetName()
+			}
+			issue.Score = s.Score(score.Input{
+				Labels:       labels,
+				Body:         issue.GetBody(),
+				Comments:     issue.GetComments(),
+				UpdatedAt:    issue.GetUpdatedAt().Time,
+				RepoActivity: activity[repoKey(issue)],
+			})
+		}
+	}
+}
+
+// repoActivity returns the share of issues updated within activityWindow for
+// each repository found in issues.
+func repoActivity(issues client.Issues, now time.Time) map[string]float64 {
+	total := make(map[string]int)
+	recent := make(map[string]int)
+	seen := make(map[string]struct{})
+	for _, is := range issues {
+		for _, issue := range is {
+			if _, ok := seen[issue.GetURL()]; ok {
+				continue
+			}
+			seen[issue.GetURL()] = struct{}{}
+			key := repoKey(issue)
+			total[key]++
+			if now.Sub(issue.GetUpdatedAt().Time) <= activityWindow {
+				recent[key]++
+			}
+		}
+	}
+
+	activity := make(map[string]float64, len(total))
+	for k, n := range total {
+		activity[k] = float64(recent[k]) / float64(n)
+	}
+	return activity
+}
+
+func repoKey(issue *client.Issue) string {
+	owner, repo, _ := config.ParseRepoURL(issue.GetURL())
+	return owner + "/" + repo
+}
+
+type topPick struct {
+	category string
+	issue    *client.Issue
+}
+
+// topPicks returns the n best scored issues over all categories. An issue
+// listed in several categories is only picked once.
+func topPicks(issues client.Issues, n int) []topPick {
+	var picks []topPick
+	seen := make(map[string]struct{})
+	for _, k := range slices.Sorted(maps.Keys(issues)) {
+		for _, issue := range issues[k] {
+			if _, ok := seen[issue.GetURL()]; ok {
+				continue
+			}
+			seen[issue.GetURL()] = struct{}{}
+			picks = append(picks, topPick{category: k, issue: issue})
+		}
+	}
+
+	sort.SliceStable(picks, func(i, j int) bool {
+		a, b := picks[i].issue, picks[j].issue
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return a.GetUpdatedAt().Time.After(b.GetUpdatedAt().Time)
+	})
+	if len(picks) > n {
+		picks = picks[:n]
+	}
+	return picks
+}
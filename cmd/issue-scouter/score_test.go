@@ -0,0 +1,101 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func newScoredIssue(url, label string, updatedAt time.Time, score float64) *client.Issue {
+	return &client.Issue{
+		Issue: &github.Issue{
+			Title:     github.Ptr(url),
+			URL:       github.Ptr(url),
+			UpdatedAt: &github.Timestamp{Time: updatedAt},
+			Labels:    []*github.Label{{Name: github.Ptr(label)}},
+		},
+		Score: score,
+	}
+}
+
+func TestScoreIssues(t *testing.T) {
+	now := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	c := &config.Config{
+		Scoring: config.Scoring{
+			Enabled:         true,
+			Labels:          map[string]float64{"good first issue": 3, "help wanted": 1},
+			RecencyHalfLife: 30,
+			Weights:         config.ScoringWeights{Labels: 1},
+		},
+	}
+	issues := client.Issues{
+		"a": {
+			newScoredIssue("https://github.com/owner/repo/issues/1", "help wanted", now, 0),
+			newScoredIssue("https://github.com/owner/repo/issues/2", "good first issue", now, 0),
+		},
+	}
+
+	scoreIssues(c, issues, now)
+	sortIssues(c, issues)
+
+	assert.Equal(t, "https://github.com/owner/repo/issues/2", issues["a"][0].GetURL())
+	assert.Equal(t, 100.0, issues["a"][0].Score)
+	assert.Equal(t, 33.3, issues["a"][1].Score)
+}
+
+func TestScoreIssuesDisabled(t *testing.T) {
+	now := time.Now()
+	issues := client.Issues{
+		"a": {
+			newScoredIssue("https://github.com/owner/repo/issues/1", "help wanted", now, 0),
+			newScoredIssue("https://github.com/owner/repo/issues/2", "good first issue", now, 0),
+		},
+	}
+
+	scoreIssues(&config.Config{}, issues, now)
+
+	assert.Equal(t, "https://github.com/owner/repo/issues/1", issues["a"][0].GetURL())
+	assert.Equal(t, 0.0, issues["a"][0].Score)
+}
+
+func TestRepoActivity(t *testing.T) {
+	now := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	shared := newScoredIssue("https://github.com/owner/repo1/issues/1", "", now, 0)
+	issues := client.Issues{
+		"a": {
+			shared,
+			newScoredIssue("https://github.com/owner/repo1/issues/2", "", now.Add(-60*24*time.Hour), 0),
+			newScoredIssue("https://github.com/owner/repo2/issues/1", "", now.Add(-60*24*time.Hour), 0),
+		},
+		"b": {shared},
+	}
+
+	assert.Equal(t, map[string]float64{
+		"owner/repo1": 0.5,
+		"owner/repo2": 0,
+	}, repoActivity(issues, now))
+}
+
+func TestTopPicks(t *testing.T) {
+	now := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	shared := newScoredIssue("https://github.com/owner/repo/issues/1", "", now, 90)
+	issues := client.Issues{
+		"b": {shared, newScoredIssue("https://github.com/owner/repo/issues/2", "", now, 50)},
+		"a": {
+			shared,
+			newScoredIssue("https://github.com/owner/repo/issues/3", "", now.Add(-time.Hour), 70),
+			newScoredIssue("https://github.com/owner/repo/issues/4", "", now, 70),
+		},
+	}
+
+	got := topPicks(issues, 3)
+	assert.Len(t, got, 3)
+	assert.Equal(t, "a", got[0].category)
+	assert.Equal(t, shared, got[0].issue)
+	assert.Equal(t, "https://github.com/owner/repo/issues/4", got[1].issue.GetURL())
+	assert.Equal(t, "https://github.com/owner/repo/issues/3", got[2].issue.GetURL())
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+// sortIssues orders each category by the sort keys of the config. It runs
+// after scoreIssues, over the issues of all the searches of a category.
+func sortIssues(c *config.Config, issues client.Issues) {
+	keys := c.SortKeys()
+	for _, is := range issues {
+		sortByKeys(is, keys)
+	}
+}
+
+// sortByKeys sorts the issues by the first key and then by the next ones on
+// ties, keeping the order of issues equal on every key.
+func sortByKeys(issues []*client.Issue, keys []config.SortKey) {
+	slices.SortStableFunc(issues, func(a, b *client.Issue) int {
+		for _, k := range keys {
+			n := compareBy(k.Field, a, b)
+			if k.IsDescending() {
+				n = -n
+			}
+			if n != 0 {
+				return n
+			}
+		}
+		return 0
+	})
+}
+
+func compareBy(field string, a, b *client.Issue) int {
+	switch field {
+	case config.SortRepo:
+		return strings.Compare(strings.ToLower(repoKey(a)), strings.ToLower(repoKey(b)))
+	case config.SortCreated:
+		return a.GetCreatedAt().Compare(b.GetCreatedAt().Time)
+	case config.SortUpdated:
+		return a.GetUpdatedAt().Compare(b.GetUpdatedAt().Time)
+	case config.SortComments:
+		return cmp.Compare(a.GetComments(), b.GetComments())
+	case config.SortReactions:
+		return cmp.Compare(a.GetReactions().GetTotalCount(), b.GetReactions().GetTotalCount())
+	case config.SortScore:
+		return cmp.Compare(a.Score, b.Score)
+	}
+	return 0
+}
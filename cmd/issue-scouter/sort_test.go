@@ -0,0 +1,81 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func TestSortIssues(t *testing.T) {
+	baseTime := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	newIssue := func(title, repo string, updatedHoursAgo, comments, reactions int, score float64) *client.Issue {
+		return &client.Issue{
+			Issue: &github.Issue{
+				Title:     github.Ptr(title),
+				URL:       github.Ptr("https://github.com/" + repo + "/issues/1"),
+				CreatedAt: &github.Timestamp{Time: baseTime.Add(-time.Duration(comments) * time.Hour)},
+				UpdatedAt: &github.Timestamp{Time: baseTime.Add(-time.Duration(updatedHoursAgo) * time.Hour)},
+				Comments:  github.Ptr(comments),
+				Reactions: &github.Reactions{TotalCount: github.Ptr(reactions)},
+			},
+			Score: score,
+		}
+	}
+
+	tests := []struct {
+		name   string
+		config *config.Config
+		want   []string
+	}{
+		{
+			name:   "default order",
+			config: &config.Config{},
+			want:   []string{"a2", "a1", "b2", "b1"},
+		},
+		{
+			name:   "score first when scoring is enabled",
+			config: &config.Config{Scoring: config.Scoring{Enabled: true}},
+			want:   []string{"b1", "a2", "a1", "b2"},
+		},
+		{
+			name: "configured keys",
+			config: &config.Config{Sort: []config.SortKey{
+				{Field: config.SortReactions},
+				{Field: config.SortUpdated, Order: config.OrderAsc},
+			}},
+			want: []string{"a1", "b2", "b1", "a2"},
+		},
+		{
+			name:   "ascending comments",
+			config: &config.Config{Sort: []config.SortKey{{Field: config.SortComments, Order: config.OrderAsc}}},
+			want:   []string{"b1", "a1", "b2", "a2"},
+		},
+		{
+			name:   "descending created",
+			config: &config.Config{Sort: []config.SortKey{{Field: config.SortCreated}}},
+			want:   []string{"b1", "a1", "b2", "a2"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := client.Issues{"team-a": {
+				newIssue("b1", "owner/b", 3, 0, 1, 90),
+				newIssue("a1", "owner/a", 2, 1, 5, 50),
+				newIssue("b2", "owner/b", 1, 2, 5, 10),
+				newIssue("a2", "Owner/a", 0, 3, 0, 50),
+			}}
+			sortIssues(tt.config, issues)
+
+			var got []string
+			for _, issue := range issues["team-a"] {
+				got = append(got, issue.GetTitle())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"math"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+	"github.com/ymtdzzz/issue-scouter/pkg/history"
+)
+
+// statsFile is the page of trends, next to the index.
+const statsFile = "STATS.md"
+
+// loadTrends computes the trends from the SQLite database, or returns empty
+// trends when it does not exist yet. The database is opened read-only, as it
+// is also used by dry runs and diff.
+func loadTrends(c *config.Config, now time.Time) (*history.Trends, error) {
+	db, err := history.OpenReadOnly(filepath.Join(c.Destination, c.SQLite.Path))
+	if errors.Is(err, fs.ErrNotExist) {
+		return &history.Trends{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return db.Trends(now, c.Stats.Weeks)
+}
+
+// generateStats renders the trends as Mermaid charts and tables.
+func generateStats(c *config.Config, trends *history.Trends, now time.Time) markdownFile {
+	var sb strings.Builder
+	sb.WriteString("# Statistics\n\n")
+	sb.WriteString(fmt.Sprintf("Last Updated: %s\n\n", now.Format("2006-01-02 15:04:05")))
+
+	if len(trends.Weeks) == 0 {
+		sb.WriteString("No runs recorded yet.\n")
+		return markdownFile{
+			pathRelative: filepath.Join(c.Destination, statsFile),
+			content:      sb.String(),
+		}
+	}
+
+	sb.WriteString(fmt.Sprintf("Trends of the last %d weeks, from %d runs. Weeks start on Monday.\n\n", c.Stats.Weeks, trends.Runs))
+
+	weeks := make([]string, len(trends.Weeks))
+	total := make([]float64, len(trends.Weeks))
+	age := make([]float64, len(trends.Weeks))
+	added := make([]float64, len(trends.Weeks))
+	gone := make([]float64, len(trends.Weeks))
+	for i, w := range trends.Weeks {
+		weeks[i] = w.Start.Format("Jan 2")
+		total[i] = float64(w.Total)
+		age[i] = w.AverageAge
+		added[i] = float64(w.Added)
+		gone[i] = float64(w.Gone)
+	}
+	first, last := trends.Weeks[0], trends.Weeks[len(trends.Weeks)-1]
+
+	sb.WriteString("## Open Opportunities\n\n")
+	writeChart(&sb, "Open issues", weeks, "Issues", chartSeries{"line", total})
+	sb.WriteString("| Category | Open | Change | Average Age |\n")
+	sb.WriteString("| --- | --- | --- | --- |\n")
+	for _, k := range trends.Categories {
+		averageAge := "-"
+		if last.Open[k] > 0 {
+			averageAge = fmt.Sprintf("%.0f days", last.Age[k])
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %d | %+d | %s |\n", escapeText(k), last.Open[k], last.Open[k]-first.Open[k], averageAge))
+	}
+	sb.WriteString("\n")
+	for _, k := range trends.Categories {
+		open := make([]float64, len(trends.Weeks))
+		for i, w := range trends.Weeks {
+			open[i] = float64(w.Open[k])
+		}
+		sb.WriteString(fmt.Sprintf("### %s\n\n", escapeText(k)))
+		writeChart(&sb, "Open issues in "+k, weeks, "Issues", chartSeries{"line", open})
+	}
+
+	sb.WriteString("## Added and Gone per Week\n\n")
+	sb.WriteString("Bars are the issues found for the first time, the line the issues not found anymore because they were closed, assigned or lost their labels.\n\n")
+	writeChart(&sb, "Issues added and gone", weeks, "Issues", chartSeries{"bar", added}, chartSeries{"line", gone})
+
+	sb.WriteString("## Average Age\n\n")
+	writeChart(&sb, "Average age of open issues", weeks, "Days", chartSeries{"line", age})
+
+	if len(trends.Repositories) > 0 {
+		sb.WriteString("## Fastest-Moving Repositories\n\n")
+		sb.WriteString("Repositories with the most issues added and gone over the period. Days Open is the median time between the creation of the gone issues and their disappearance.\n\n")
+		sb.WriteString("| Repository | Added | Gone | Open | Days Open |\n")
+		sb.WriteString("| --- | --- | --- | --- | --- |\n")
+		for _, r := range trends.Repositories[:min(len(trends.Repositories), c.Stats.TopRepositories)] {
+			daysOpen := "-"
+			if r.Gone > 0 {
+				daysOpen = fmt.Sprintf("%.0f", r.MedianDaysOpen)
+			}
+			sb.WriteString(fmt.Sprintf("| [%s](https://github.com/%s) | %d | %d | %d | %s |\n", escapeText(r.Repository), r.Repository, r.Added, r.Gone, r.Open, daysOpen))
+		}
+	}
+
+	return markdownFile{
+		pathRelative: filepath.Join(c.Destination, statsFile),
+		content:      sb.String(),
+	}
+}
+
+type chartSeries struct {
+	kind   string
+	values []float64
+}
+
+// writeChart writes a Mermaid XY chart, which GitHub renders natively.
+func writeChart(sb *strings.Builder, title string, labels []string, axis string, series ...chartSeries) {
+	quoted := make([]string, len(labels))
+	for i, l := range labels {
+		quoted[i] = mermaidString(l)
+	}
+	sb.WriteString("```mermaid\n")
+	sb.WriteString("xychart-beta\n")
+	sb.WriteString(fmt.Sprintf("    title %s\n", mermaidString(title)))
+	sb.WriteString(fmt.Sprintf("    x-axis [%s]\n", strings.Join(quoted, ", ")))
+	sb.WriteString(fmt.Sprintf("    y-axis %s\n", mermaidString(axis)))
+	for _, s := range series {
+		values := make([]string, len(s.values))
+		for i, v := range s.values {
+			values[i] = fmt.Sprintf("%.0f", math.Round(v))
+		}
+		sb.WriteString(fmt.Sprintf("    %s [%s]\n", s.kind, strings.Join(values, ", ")))
+	}
+	sb.WriteString("```\n\n")
+}
+
+// mermaidString quotes s for Mermaid, which has no escape for double quotes.
+func mermaidString(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+}
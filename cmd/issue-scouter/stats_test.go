@@ -0,0 +1,111 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+	"github.com/ymtdzzz/issue-scouter/pkg/history"
+)
+
+func TestGenerateStats(t *testing.T) {
+	now := time.Date(2025, 3, 19, 10, 0, 0, 0, time.UTC)
+	co := &config.Config{
+		Destination: "output",
+		Stats:       config.Stats{Enabled: true, Weeks: 2, TopRepositories: 1},
+	}
+	tests := []struct {
+		name   string
+		trends *history.Trends
+		want   string
+	}{
+		{
+			name:   "without runs",
+			trends: &history.Trends{},
+			want: "# Statistics\n\n" +
+				"Last Updated: 2025-03-19 10:00:00\n\n" +
+				"No runs recorded yet.\n",
+		},
+		{
+			name: "with runs",
+			trends: &history.Trends{
+				Runs: 3,
+				Weeks: []history.Week{
+					{
+						Start: time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC),
+						Open:  map[string]int{"Web \"UI\"": 2},
+						Age:   map[string]float64{"Web \"UI\"": 12.5},
+						Total: 2, AverageAge: 12.5,
+						Added: 2,
+					},
+					{
+						Start: time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC),
+						Open:  map[string]int{"Web \"UI\"": 1},
+						Age:   map[string]float64{"Web \"UI\"": 8.4},
+						Total: 1, AverageAge: 8.4,
+						Gone: 1,
+					},
+				},
+				Categories: []string{"Web \"UI\""},
+				Repositories: []history.RepositoryTrend{
+					{Repository: "owner/x", Added: 1, Gone: 1, Open: 0, MedianDaysOpen: 35},
+					{Repository: "owner/y", Added: 1, Open: 1},
+				},
+			},
+			want: "# Statistics\n\n" +
+				"Last Updated: 2025-03-19 10:00:00\n\n" +
+				"Trends of the last 2 weeks, from 3 runs. Weeks start on Monday.\n\n" +
+				"## Open Opportunities\n\n" +
+				"```mermaid\n" +
+				"xychart-beta\n" +
+				"    title \"Open issues\"\n" +
+				"    x-axis [\"Mar 10\", \"Mar 17\"]\n" +
+				"    y-axis \"Issues\"\n" +
+				"    line [2, 1]\n" +
+				"```\n\n" +
+				"| Category | Open | Change | Average Age |\n" +
+				"| --- | --- | --- | --- |\n" +
+				"| Web \"UI\" | 1 | -1 | 8 days |\n\n" +
+				"### Web \"UI\"\n\n" +
+				"```mermaid\n" +
+				"xychart-beta\n" +
+				"    title \"Open issues in Web 'UI'\"\n" +
+				"    x-axis [\"Mar 10\", \"Mar 17\"]\n" +
+				"    y-axis \"Issues\"\n" +
+				"    line [2, 1]\n" +
+				"```\n\n" +
+				"## Added and Gone per Week\n\n" +
+				"Bars are the issues found for the first time, the line the issues not found anymore because they were closed, assigned or lost their labels.\n\n" +
+				"```mermaid\n" +
+				"xychart-beta\n" +
+				"    title \"Issues added and gone\"\n" +
+				"    x-axis [\"Mar 10\", \"Mar 17\"]\n" +
+				"    y-axis \"Issues\"\n" +
+				"    bar [2, 0]\n" +
+				"    line [0, 1]\n" +
+				"```\n\n" +
+				"## Average Age\n\n" +
+				"```mermaid\n" +
+				"xychart-beta\n" +
+				"    title \"Average age of open issues\"\n" +
+				"    x-axis [\"Mar 10\", \"Mar 17\"]\n" +
+				"    y-axis \"Days\"\n" +
+				"    line [13, 8]\n" +
+				"```\n\n" +
+				"## Fastest-Moving Repositories\n\n" +
+				"Repositories with the most issues added and gone over the period. Days Open is the median time between the creation of the gone issues and their disappearance.\n\n" +
+				"| Repository | Added | Gone | Open | Days Open |\n" +
+				"| --- | --- | --- | --- | --- |\n" +
+				"| [owner/x](https://github.com/owner/x) | 1 | 1 | 0 | 35 |\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := generateStats(co, tt.trends, now)
+			assert.Equal(t, "output/STATS.md", got.pathRelative)
+			assert.Equal(t, tt.want, got.content)
+		})
+	}
+}
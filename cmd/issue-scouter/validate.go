@@ -0,0 +1,55 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func validateCommand(args []string) error {
+	fs := newFlagSet("validate", "[flags]")
+	configFile := configFlag(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(configFile.files) == 0 {
+		return errors.New("no config file specified, use --config or INPUT_CONFIG_FILE")
+	}
+
+	return reportValidation(os.Stdout, configFile.files, config.ValidateFile(configFile.files...))
+}
+
+func reportValidation(w io.Writer, configFiles []string, err error) error {
+	configFile := strings.Join(configFiles, ", ")
+	if err == nil {
+		fmt.Fprintf(w, "%s: OK\n", configFile)
+		return nil
+	}
+
+	var errs config.ValidationErrors
+	if !errors.As(err, &errs) {
+		return fmt.Errorf("failed to load config: %w", err)
+	}
+	for _, e := range errs {
+		loc := e.File
+		if loc == "" {
+			loc = configFile
+		}
+		if e.Line > 0 {
+			loc += fmt.Sprintf(":%d", e.Line)
+		}
+		if e.Column > 0 {
+			loc += fmt.Sprintf(":%d", e.Column)
+		}
+		if e.Path != "" {
+			fmt.Fprintf(w, "%s: %s: %s\n", loc, e.Path, e.Message)
+		} else {
+			fmt.Fprintf(w, "%s: %s\n", loc, e.Message)
+		}
+	}
+	return fmt.Errorf("%d problems found in %s", len(errs), configFile)
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func TestReportValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		want    string
+		wantErr string
+	}{
+		{
+			name: "valid",
+			err:  nil,
+			want: "config.yml: OK\n",
+		},
+		{
+			name: "validation errors",
+			err: config.ValidationErrors{
+				{Path: "label", Line: 6, Column: 1, Message: `unknown field "label"`},
+				{Line: 8, Message: "cannot unmarshal !!str `abc` into int"},
+				{Path: "repositories", Message: "at least one category is required"},
+			},
+			want: "config.yml:6:1: label: unknown field \"label\"\n" +
+				"config.yml:8: cannot unmarshal !!str `abc` into int\n" +
+				"config.yml: repositories: at least one category is required\n",
+			wantErr: "3 problems found in config.yml",
+		},
+		{
+			name:    "other error",
+			err:     errors.New("open config.yml: no such file or directory"),
+			want:    "",
+			wantErr: "failed to load config: open config.yml: no such file or directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+			err := reportValidation(&out, []string{"config.yml"}, tt.err)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
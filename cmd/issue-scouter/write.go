@@ -0,0 +1,291 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+// manifestName is the file, in the destination, listing the files written by
+// the last run. Only these files are ever removed.
+const manifestName = ".issue-scouter-manifest.json"
+
+// stagingName is the directory, in the destination, where the files are
+// written before being moved in place. It is on the same file system as the
+// destination so that moving a file is a rename, and must not be published.
+const stagingName = ".issue-scouter-staging"
+
+type manifest struct {
+	// Files are slash-separated paths relative to the destination.
+	Files []string `json:"files"`
+	// Remove lists the files of the previous run to remove. It is only set
+	// in the journal of the staging directory.
+	Remove []string `json:"remove,omitempty"`
+}
+
+// saveToFiles publishes the files in two steps. They are first written into
+// the staging directory, followed by a journal listing them and the files of
+// the previous run not generated anymore. Then they are renamed in place one
+// by one, the old files are removed and the manifest is saved. This is not an
+// atomic swap: a run which stops before writing the journal leaves the
+// previous list untouched and its staging directory is discarded, while a run
+// which stops after leaves a mix of old and new files which the next run
+// completes from the journal before doing anything else.
+func (files markdownFiles) saveToFiles(c *config.Config) error {
+	dest := c.Destination
+	if err := checkWorkspace(dest); err != nil {
+		return err
+	}
+	rels, err := files.relativeTo(dest)
+	if err != nil {
+		return err
+	}
+	if err := recoverStaging(dest); err != nil {
+		return err
+	}
+	previous, err := previousFiles(dest)
+	if err != nil {
+		return err
+	}
+
+	staging := filepath.Join(dest, stagingName)
+	if err := os.MkdirAll(staging, 0750); err != nil {
+		return fmt.Errorf("failed to create directory %s: %v", staging, err)
+	}
+	for i, f := range files {
+		path := filepath.Join(staging, filepath.FromSlash(rels[i]))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return fmt.Errorf("failed to create directory %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.WriteFile(path, []byte(f.content), 0640); err != nil {
+			return fmt.Errorf("failed to save a file: %v", err)
+		}
+	}
+
+	journal := manifest{Files: rels}
+	for _, rel := range previous {
+		if !slices.Contains(rels, rel) {
+			journal.Remove = append(journal.Remove, rel)
+		}
+	}
+	if err := writeJSON(staging, manifestName, journal); err != nil {
+		return err
+	}
+	return applyStaging(dest)
+}
+
+// recoverStaging completes a run which stopped after writing its journal,
+// and removes the staging directory of a run which stopped before.
+func recoverStaging(dest string) error {
+	// A manifest left half-written, the previous one is still in place
+	if err := os.Remove(filepath.Join(dest, manifestName+".tmp")); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+
+	staging := filepath.Join(dest, stagingName)
+	if _, err := os.Stat(filepath.Join(staging, manifestName)); err == nil {
+		log.Printf("Completing an interrupted run from %s", staging)
+		return applyStaging(dest)
+	} else if !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	if err := os.RemoveAll(staging); err != nil {
+		return fmt.Errorf("failed to remove %s: %v", staging, err)
+	}
+	return nil
+}
+
+// applyStaging moves the files listed in the journal in place, removes the
+// old files and saves the manifest. Files already moved are skipped, so that
+// it can be run again after an interruption.
+func applyStaging(dest string) error {
+	staging := filepath.Join(dest, stagingName)
+	data, err := os.ReadFile(filepath.Join(staging, manifestName))
+	if err != nil {
+		return err
+	}
+	var journal manifest
+	if err := json.Unmarshal(data, &journal); err != nil {
+		return fmt.Errorf("invalid journal %s: %v", filepath.Join(staging, manifestName), err)
+	}
+
+	for _, rel := range journal.Files {
+		if err := checkRelative(dest, rel); err != nil {
+			return err
+		}
+		staged := filepath.Join(staging, filepath.FromSlash(rel))
+		if _, err := os.Stat(staged); errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		path := filepath.Join(dest, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			return fmt.Errorf("failed to create directory %s: %v", filepath.Dir(path), err)
+		}
+		if err := os.Rename(staged, path); err != nil {
+			return fmt.Errorf("failed to move %s in place: %v", rel, err)
+		}
+	}
+	for _, rel := range journal.Remove {
+		log.Printf("Removing old file: %s", rel)
+		if err := removeFile(dest, rel); err != nil {
+			return err
+		}
+	}
+
+	if err := writeJSON(dest, manifestName, manifest{Files: journal.Files}); err != nil {
+		return err
+	}
+	if err := os.RemoveAll(staging); err != nil {
+		return fmt.Errorf("failed to remove %s: %v", staging, err)
+	}
+	return nil
+}
+
+// relativeTo returns the slash-separated paths of the files relative to
+// dest, or an error if a file is outside of it.
+func (files markdownFiles) relativeTo(dest string) ([]string, error) {
+	rels := make([]string, len(files))
+	for i, f := range files {
+		rel, err := filepath.Rel(dest, f.pathRelative)
+		if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return nil, fmt.Errorf("refusing to write %s outside of the destination %s", f.pathRelative, dest)
+		}
+		rels[i] = filepath.ToSlash(rel)
+	}
+	return rels, nil
+}
+
+// checkWorkspace refuses destinations outside of the working directory,
+// including through symbolic links.
+func checkWorkspace(dest string) error {
+	if err := config.CheckDestination(dest); err != nil {
+		return fmt.Errorf("refusing to write to destination %s: %v", dest, err)
+	}
+	wd, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+	root, err := filepath.EvalSymlinks(wd)
+	if err != nil {
+		return err
+	}
+	resolved, err := resolveExisting(filepath.Join(wd, dest))
+	if err != nil {
+		return err
+	}
+	if rel, err := filepath.Rel(root, resolved); err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("refusing to write to destination %s: it resolves to %s, outside of the working directory", dest, resolved)
+	}
+	return nil
+}
+
+// resolveExisting evaluates the symbolic links of the longest existing
+// prefix of path.
+func resolveExisting(path string) (string, error) {
+	resolved, err := filepath.EvalSymlinks(path)
+	if err == nil {
+		return resolved, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return "", err
+	}
+	parent := filepath.Dir(path)
+	if parent == path {
+		return path, nil
+	}
+	resolvedParent, err := resolveExisting(parent)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(resolvedParent, filepath.Base(path)), nil
+}
+
+// previousFiles returns the files written by the previous run. Without a
+// manifest, they are the files of the issues directory, which older versions
+// replaced as a whole.
+func previousFiles(dest string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(dest, manifestName))
+	if err == nil {
+		var m manifest
+		if err := json.Unmarshal(data, &m); err != nil {
+			return nil, fmt.Errorf("invalid manifest %s: %v", filepath.Join(dest, manifestName), err)
+		}
+		return m.Files, nil
+	}
+	if !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	var files []string
+	err = filepath.WalkDir(filepath.Join(dest, "issues"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return filepath.SkipDir
+			}
+			return err
+		}
+		if d.Type().IsRegular() {
+			rel, err := filepath.Rel(dest, path)
+			if err != nil {
+				return err
+			}
+			files = append(files, filepath.ToSlash(rel))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list old files: %v", err)
+	}
+	return files, nil
+}
+
+// writeJSON replaces the file name in dir with v, through a temporary file
+// in dir.
+func writeJSON(dir, name string, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := filepath.Join(dir, name+".tmp")
+	if err := os.WriteFile(tmp, append(data, '\n'), 0640); err != nil {
+		return fmt.Errorf("failed to save %s: %v", name, err)
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("failed to save %s: %v", name, err)
+	}
+	return nil
+}
+
+// removeFile removes a file listed in the manifest, and the directories left
+// empty up to dest.
+func removeFile(dest, rel string) error {
+	if err := checkRelative(dest, rel); err != nil {
+		return err
+	}
+	path := filepath.Join(dest, filepath.FromSlash(rel))
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove %s: %v", path, err)
+	}
+	for dir := filepath.Dir(path); dir != filepath.Clean(dest); dir = filepath.Dir(dir) {
+		if os.Remove(dir) != nil {
+			break
+		}
+	}
+	return nil
+}
+
+// checkRelative refuses paths from a manifest which point outside of dest.
+func checkRelative(dest, rel string) error {
+	if rel == "" || strings.HasPrefix(rel, "/") || slices.Contains(strings.Split(rel, "/"), "..") {
+		return fmt.Errorf("refusing to touch %s outside of the destination %s", rel, dest)
+	}
+	return nil
+}
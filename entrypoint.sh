@@ -6,13 +6,18 @@ INPUT_DRY_RUN="${INPUT_DRY_RUN:-false}"
 
 echo "Dry-run mode: ${INPUT_DRY_RUN}"
 
-/app/issue-scouter
+# Files are always written in the action; the dry_run input only skips
+# pushing the commit below, unlike the --dry-run flag of the CLI
+/app/issue-scouter run
 
 git config --global --add safe.directory /github/workspace
 git config --global user.name "github-actions[bot]"
 git config --global user.email "github-actions[bot]@users.noreply.github.com"
 
-git add --all
+# Never publish the staging directory of an interrupted run
+git add --all -- . \
+  ':(exclude,glob)**/.issue-scouter-staging/**' \
+  ':(exclude,glob)**/.issue-scouter-manifest.json.tmp'
 git commit -m "Update issue list"
 
 if [ "${INPUT_DRY_RUN}" = "true" ]; then
@@ -0,0 +1,143 @@
+package claims
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+// DefaultPatterns match comments asking to work on an issue in English,
+// Spanish, French, German, Portuguese, Chinese and Japanese. They are used
+// when claims.patterns is empty.
+var DefaultPatterns = []string{
+	`\bi(['’]?d| would) (like|love) to (work on|take|tackle|pick up|try)\b`,
+	`\bi (want|wanna) to (work on|take|tackle) (this|it)\b`,
+	`\b(can|could|may) i (work on|take|pick up|grab|tackle|try)\b`,
+	`\bi['’]?ll (work on|take|tackle|pick up) (this|it)\b`,
+	`\bi['’]?m (working on|taking|on) (this|it)\b`,
+	`\b(please )?assign (this |it )?(to )?me\b`,
+	`\b(me gustar[ií]a|quiero|puedo) (trabajar en|tomar|encargarme de)\b`,
+	`\bas[ií]gn(a|e)me\b`,
+	`\bje (voudrais|veux|peux|souhaite) (travailler sur|prendre|m['’]occuper de)\b`,
+	`\bpuis-je (travailler|prendre|m['’]occuper)\b`,
+	`\bich (möchte|würde gerne|kann|werde) (das|daran|mich darum)\b`,
+	`\bkann ich (das|daran|mich darum)\b`,
+	`\b(eu )?(gostaria de|quero|posso) (trabalhar|pegar|assumir)\b`,
+	`我(想|来|可以)(做|认领|处理|负责|修)`,
+	`认领`,
+	`(担当|対応|作業)(させて|したい|します)`,
+	`やってみたい`,
+}
+
+// DefaultAcknowledgements match maintainer replies accepting a claim. They
+// are used when claims.acknowledgements is empty.
+var DefaultAcknowledgements = []string{
+	`\b(go ahead|sure|assigned|all yours|feel free|yes|of course|please do|sounds good)\b`,
+	`\b(adelante|claro|asignad[oa]|por supuesto)\b`,
+	`\b(vas-y|allez-y|bien sûr|assigné|avec plaisir)`,
+	`\b(gerne|nur zu|klar|zugewiesen)\b`,
+	`\b(pode|claro|atribu[ií]d[oa]|fique à vontade)\b`,
+	`(好的|可以|没问题|已分配)`,
+	`(お願いします|どうぞ|アサインしました)`,
+}
+
+// Comment is what the detector needs to know about a comment.
+type Comment struct {
+	Author string
+	// Maintainer reports whether the author maintains the repository.
+	Maintainer bool
+	Body       string
+	CreatedAt  time.Time
+}
+
+// Claim is a request to work on an issue.
+type Claim struct {
+	Claimer string
+	At      time.Time
+	// Acknowledged reports whether a maintainer accepted the claim in a later
+	// comment.
+	Acknowledged bool
+}
+
+type Detector struct {
+	patterns         []*regexp.Regexp
+	acknowledgements []*regexp.Regexp
+	within           time.Duration
+	requireAck       bool
+	now              time.Time
+}
+
+// New returns a detector of claims made before now, or an error when a
+// pattern is not a valid regular expression.
+func New(c config.Claims, now time.Time) (*Detector, error) {
+	patterns, err := compile(c.Patterns, DefaultPatterns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid claim pattern: %w", err)
+	}
+	acknowledgements, err := compile(c.Acknowledgements, DefaultAcknowledgements)
+	if err != nil {
+		return nil, fmt.Errorf("invalid acknowledgement pattern: %w", err)
+	}
+	return &Detector{
+		patterns:         patterns,
+		acknowledgements: acknowledgements,
+		within:           time.Duration(c.Within),
+		requireAck:       c.RequireAcknowledgement,
+		now:              now,
+	}, nil
+}
+
+// compile compiles the patterns, or the defaults when there is none, to be
+// matched case-insensitively.
+func compile(patterns, defaults []string) ([]*regexp.Regexp, error) {
+	if len(patterns) == 0 {
+		patterns = defaults
+	}
+	res := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = re
+	}
+	return res, nil
+}
+
+// Detect returns the most recent claim in the comments, oldest first, or nil
+// when the issue is not likely claimed. Claims of maintainers and claims
+// older than the recency window are ignored. When acknowledgements are
+// required, only acknowledged claims count.
+func (d *Detector) Detect(comments []Comment) *Claim {
+	var claim, acknowledged *Claim
+	for _, comment := range comments {
+		if comment.Maintainer {
+			if claim != nil && !claim.Acknowledged && matchAny(d.acknowledgements, comment.Body) {
+				claim.Acknowledged = true
+				acknowledged = claim
+			}
+			continue
+		}
+		if d.within > 0 && d.now.Sub(comment.CreatedAt) > d.within {
+			continue
+		}
+		if matchAny(d.patterns, comment.Body) {
+			claim = &Claim{Claimer: comment.Author, At: comment.CreatedAt}
+		}
+	}
+	if d.requireAck {
+		return acknowledged
+	}
+	return claim
+}
+
+func matchAny(res []*regexp.Regexp, s string) bool {
+	for _, re := range res {
+		if re.MatchString(s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,137 @@
+package claims
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+var now = time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+
+func daysAgo(days int) time.Time {
+	return now.Add(-time.Duration(days) * 24 * time.Hour)
+}
+
+func TestDefaultPatterns(t *testing.T) {
+	tests := []struct {
+		body string
+		want bool
+	}{
+		{"Hi! I'd like to work on this.", true},
+		{"I would love to take this one", true},
+		{"Can I pick up this issue?", true},
+		{"I'm working on it", true},
+		{"Please assign this to me", true},
+		{"Me gustaría trabajar en esto", true},
+		{"Je voudrais travailler sur ce ticket", true},
+		{"Ich möchte das übernehmen", true},
+		{"Gostaria de trabalhar nisso", true},
+		{"我想认领这个问题", true},
+		{"この issue を担当させてください", true},
+		{"I'm taking notes: this also fails on Windows", false},
+		{"Can I reproduce this on Linux too?", false},
+		{"This is still happening with 1.2", false},
+	}
+
+	d, err := New(config.Claims{}, now)
+	assert.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.body, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchAny(d.patterns, tt.body))
+		})
+	}
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   config.Claims
+		comments []Comment
+		want     *Claim
+	}{
+		{
+			name: "no claim",
+			comments: []Comment{
+				{Author: "user", Body: "Same here", CreatedAt: daysAgo(3)},
+			},
+		},
+		{
+			name: "claim",
+			comments: []Comment{
+				{Author: "user", Body: "Can I take this?", CreatedAt: daysAgo(3)},
+			},
+			want: &Claim{Claimer: "user", At: daysAgo(3)},
+		},
+		{
+			name: "most recent claim wins",
+			comments: []Comment{
+				{Author: "first", Body: "Can I take this?", CreatedAt: daysAgo(10)},
+				{Author: "second", Body: "I'll work on it", CreatedAt: daysAgo(3)},
+			},
+			want: &Claim{Claimer: "second", At: daysAgo(3)},
+		},
+		{
+			name: "maintainers do not claim",
+			comments: []Comment{
+				{Author: "owner", Maintainer: true, Body: "I'll take a look at it", CreatedAt: daysAgo(3)},
+			},
+		},
+		{
+			name:   "claim outside of the recency window",
+			config: config.Claims{Within: config.Duration(7 * 24 * time.Hour)},
+			comments: []Comment{
+				{Author: "user", Body: "Can I take this?", CreatedAt: daysAgo(30)},
+			},
+		},
+		{
+			name:   "acknowledged claim",
+			config: config.Claims{RequireAcknowledgement: true},
+			comments: []Comment{
+				{Author: "user", Body: "Can I take this?", CreatedAt: daysAgo(5)},
+				{Author: "owner", Maintainer: true, Body: "Sure, assigned!", CreatedAt: daysAgo(4)},
+			},
+			want: &Claim{Claimer: "user", At: daysAgo(5), Acknowledged: true},
+		},
+		{
+			name:   "acknowledgement before the claim",
+			config: config.Claims{RequireAcknowledgement: true},
+			comments: []Comment{
+				{Author: "owner", Maintainer: true, Body: "Sure, PRs are welcome", CreatedAt: daysAgo(5)},
+				{Author: "user", Body: "Can I take this?", CreatedAt: daysAgo(4)},
+			},
+		},
+		{
+			name: "acknowledged claim followed by another claim",
+			comments: []Comment{
+				{Author: "first", Body: "Can I take this?", CreatedAt: daysAgo(10)},
+				{Author: "owner", Maintainer: true, Body: "Go ahead", CreatedAt: daysAgo(9)},
+				{Author: "second", Body: "Is anyone working on it? I'd like to try", CreatedAt: daysAgo(1)},
+			},
+			want: &Claim{Claimer: "second", At: daysAgo(1)},
+		},
+		{
+			name:   "custom patterns",
+			config: config.Claims{Patterns: []string{`\bdibs\b`}},
+			comments: []Comment{
+				{Author: "user", Body: "Can I take this?", CreatedAt: daysAgo(3)},
+				{Author: "other", Body: "DIBS", CreatedAt: daysAgo(2)},
+			},
+			want: &Claim{Claimer: "other", At: daysAgo(2)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := New(tt.config, now)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, d.Detect(tt.comments))
+		})
+	}
+}
+
+func TestNewInvalidPattern(t *testing.T) {
+	_, err := New(config.Claims{Patterns: []string{"("}}, now)
+	assert.Error(t, err)
+}
@@ -5,12 +5,12 @@ import (
 	"fmt"
 	"log"
 	"maps"
-	"os"
 	"slices"
-	"sort"
 	"strings"
+	"time"
 
 	"github.com/google/go-github/v69/github"
+	"github.com/ymtdzzz/issue-scouter/pkg/claims"
 	"github.com/ymtdzzz/issue-scouter/pkg/config"
 	"golang.org/x/oauth2"
 )
@@ -24,15 +24,39 @@ type client struct {
 	ghc    *github.Client
 	config *config.Config
 	cache  map[string][]*github.Issue
+
+	repoCache     map[string]*Repository
+	categoryRepos Repositories
+	prCache       map[string]*PullRequest
+	commentCache  map[string][]*github.IssueComment
+}
+
+// Issue is a search result together with what issue-scouter computed for
+// it. The underlying github.Issue may be shared between categories.
+type Issue struct {
+	*github.Issue
+	// Repo is nil unless the repository metadata was fetched.
+	Repo *Repository
+	// PullRequest is only set for pull requests.
+	PullRequest *PullRequest
+	// RecentComments holds the most recent comments, oldest first, when
+	// needed by the config.
+	RecentComments []*github.IssueComment
+	// Activity is only set when the comment activity is needed by the
+	// config.
+	Activity *Activity
+	Score    float64
+	// AlsoIn lists the other categories the issue was found in, when
+	// duplicates are cross-referenced.
+	AlsoIn []string
 }
 
-type Issues map[string][]*github.Issue
+type Issues map[string][]*Issue
 
-func NewClient(config *config.Config) *client {
+func NewClient(config *config.Config, token string) *client {
 	var ghc *github.Client
-	token := os.Getenv("GITHUB_TOKEN")
 	if token == "" {
-		log.Println("GITHUB_TOKEN is not set, initialize Github client without credentials")
+		log.Println("GitHub token is not set, initialize Github client without credentials")
 		ghc = github.NewClient(nil)
 
 		return &client{
@@ -62,6 +86,12 @@ func (c *client) GetClient() *github.Client {
 func (c *client) FetchIssues() (Issues, error) {
 	issues := Issues{}
 	chunkSize := 50
+	ctx := context.Background()
+	c.categoryRepos = Repositories{}
+	detector, err := claims.New(c.config.Claims, time.Now())
+	if err != nil {
+		return nil, err
+	}
 
 	for _, k := range slices.Sorted(maps.Keys(c.config.Repos)) {
 		var gis []*github.Issue
@@ -69,43 +99,120 @@ func (c *client) FetchIssues() (Issues, error) {
 		log.Printf("")
 		log.Printf(">> Fetching issues for %s <<", k)
 
+		filters := c.config.FiltersFor(k)
+		qualifiers := filters.Qualifiers(time.Now())
+		withMetadata := c.config.NeedsRepositories(k)
+
 		ownerRepos := make([]string, 0, len(c.config.Repos[k]))
+		repos := make(map[string]*Repository, len(c.config.Repos[k]))
 		for _, r := range c.config.Repos[k] {
 			owner, repo, err := config.ParseRepoURL(r)
 			if err != nil {
 				log.Printf("Failed to parse repository URL: %v", err)
 				continue
 			}
+
+			cr := &CategoryRepository{Repository: newRepository(owner, repo)}
+			if withMetadata {
+				meta, err := c.fetchRepository(ctx, owner, repo, len(filters.Languages) > 0)
+				if err != nil {
+					// Keep the repository, the issues are still worth listing
+					log.Printf("Failed to fetch repository metadata: %v", err)
+				} else {
+					cr.Repository = meta
+					repos[strings.ToLower(owner+"/"+repo)] = meta
+					cr.SkipReason = skipReason(meta, filters, time.Now())
+				}
+			}
+			c.categoryRepos[k] = append(c.categoryRepos[k], cr)
+			if cr.SkipReason != "" {
+				log.Printf("Skip %s/%s: %s", owner, repo, cr.SkipReason)
+				continue
+			}
 			ownerRepos = append(ownerRepos, owner+"/"+repo)
 		}
 
-		// Process repositories in chunks
-		for i := 0; i < len(ownerRepos); i += chunkSize {
-			end := i + chunkSize
-			if end > len(ownerRepos) {
-				end = len(ownerRepos)
+		// Repositories with label aliases are searched with other labels
+		var labelQueries []string
+		byLabels := make(map[string][]string)
+		for _, r := range ownerRepos {
+			q := c.config.LabelsForQuery(r)
+			if _, ok := byLabels[q]; !ok {
+				labelQueries = append(labelQueries, q)
 			}
-			chunk := ownerRepos[i:end]
+			byLabels[q] = append(byLabels[q], r)
+		}
 
-			is, err := c.fetchIssuesByRepos(chunk)
-			if err != nil {
-				log.Printf("Failed to fetch issues for chunk in %s: %v", k, err)
-				continue
+		for _, itemType := range c.config.Types() {
+			for _, q := range labelQueries {
+				group := byLabels[q]
+				// Process repositories in chunks
+				for i := 0; i < len(group); i += chunkSize {
+					end := i + chunkSize
+					if end > len(group) {
+						end = len(group)
+					}
+					chunk := group[i:end]
+
+					is, err := c.fetchIssuesByRepos(chunk, itemType, qualifiers)
+					if err != nil {
+						log.Printf("Failed to fetch %s items for chunk in %s: %v", itemType, k, err)
+						continue
+					}
+					gis = append(gis, is...)
+				}
 			}
-			gis = append(gis, is...)
 		}
 
-		issues[k] = gis
+		issues[k] = make([]*Issue, 0, len(gis))
+		for _, gi := range gis {
+			owner, repo, _ := config.ParseRepoURL(gi.GetRepositoryURL())
+			issue := &Issue{Issue: gi, Repo: repos[strings.ToLower(owner+"/"+repo)]}
+			if gi.IsPullRequest() {
+				pr, err := c.fetchPullRequest(ctx, owner, repo, gi.GetNumber())
+				if err != nil {
+					log.Printf("Failed to fetch pull request details: %v", err)
+					pr = &PullRequest{Draft: gi.GetDraft()}
+				}
+				issue.PullRequest = pr
+			}
+			if n := c.config.CommentsToFetch(k); n > 0 {
+				comments, err := c.fetchComments(ctx, owner, repo, gi.GetNumber(), gi.GetComments(), n)
+				if err != nil {
+					log.Printf("Failed to fetch comments: %v", err)
+				}
+				issue.RecentComments = comments
+				if c.config.NeedsComments(k) && err == nil {
+					issue.Activity = summarizeComments(comments, detector)
+				}
+			}
+			if filters.ExcludeClaimed != nil && *filters.ExcludeClaimed && issue.Activity != nil && issue.Activity.Claim != nil {
+				log.Printf("Skip %s: claimed in comments", gi.GetHTMLURL())
+				continue
+			}
+			issues[k] = append(issues[k], issue)
+		}
 	}
 	return issues, nil
 }
 
-func (c *client) checkCache(ownerRepo []string) ([]*github.Issue, []string) {
+// cacheKey identifies the results of a repository. Categories may search the
+// same repository with different qualifiers, so they are part of the key
+// together with the item type.
+func cacheKey(repo, itemType, qualifiers string) string {
+	key := repo + " is:" + itemType
+	if qualifiers == "" {
+		return key
+	}
+	return key + " " + qualifiers
+}
+
+func (c *client) checkCache(ownerRepo []string, itemType, qualifiers string) ([]*github.Issue, []string) {
 	var allIssues []*github.Issue
 	reposToFetch := make([]string, 0, len(ownerRepo))
 
 	for _, repo := range ownerRepo {
-		if issues, ok := c.cache[repo]; ok {
+		if issues, ok := c.cache[cacheKey(repo, itemType, qualifiers)]; ok {
 			log.Printf("Cache hit for %s", repo)
 			allIssues = append(allIssues, issues...)
 		} else {
@@ -116,9 +223,11 @@ func (c *client) checkCache(ownerRepo []string) ([]*github.Issue, []string) {
 	return allIssues, reposToFetch
 }
 
-func (c *client) fetchIssuesByRepos(ownerRepo []string) ([]*github.Issue, error) {
+// fetchIssuesByRepos searches the items of the repositories, which must share
+// the same labels, see config.Config.LabelsFor.
+func (c *client) fetchIssuesByRepos(ownerRepo []string, itemType, qualifiers string) ([]*github.Issue, error) {
 	// Get cached issues and identify remaining repos to fetch
-	issues, reposToFetch := c.checkCache(ownerRepo)
+	issues, reposToFetch := c.checkCache(ownerRepo, itemType, qualifiers)
 
 	if len(reposToFetch) == 0 {
 		log.Printf("All issues are fetched from cache!")
@@ -133,7 +242,10 @@ func (c *client) fetchIssuesByRepos(ownerRepo []string) ([]*github.Issue, error)
 	}
 	reposForQuery := strings.Join(repos, " ")
 
-	q := fmt.Sprintf("%s is:open is:issue label:%s", reposForQuery, c.config.LabelsForQuery())
+	q := fmt.Sprintf("%s is:open is:%s label:%s", reposForQuery, itemType, c.config.LabelsForQuery(reposToFetch[0]))
+	if qualifiers != "" {
+		q += " " + qualifiers
+	}
 	log.Printf("Query: %s", q)
 
 	opts := &github.SearchOptions{
@@ -143,6 +255,7 @@ func (c *client) fetchIssuesByRepos(ownerRepo []string) ([]*github.Issue, error)
 		},
 	}
 
+	var fetched []*github.Issue
 	page := 1
 	for {
 		log.Printf("Fetching page %d ...", page)
@@ -151,7 +264,7 @@ func (c *client) fetchIssuesByRepos(ownerRepo []string) ([]*github.Issue, error)
 			return nil, fmt.Errorf("failed to fetch issues: %w", err)
 		}
 
-		issues = append(issues, results.Issues...)
+		fetched = append(fetched, results.Issues...)
 
 		if resp.NextPage == 0 {
 			break
@@ -160,38 +273,25 @@ func (c *client) fetchIssuesByRepos(ownerRepo []string) ([]*github.Issue, error)
 		page++
 	}
 
-	// Replace URL and cache issues per repository
-	for i := range issues {
-		replacedURL := strings.Replace(issues[i].GetURL(), API_URL_BASE, URL_BASE, 1)
-		replacedRepositoryURL := strings.Replace(issues[i].GetRepositoryURL(), API_URL_BASE, URL_BASE, 1)
-		issues[i].URL = &replacedURL
-		issues[i].RepositoryURL = &replacedRepositoryURL
+	// Replace URL and cache issues per repository. Cached issues are already
+	// processed, only the fetched ones are added.
+	for i := range fetched {
+		replacedURL := strings.Replace(fetched[i].GetURL(), API_URL_BASE, URL_BASE, 1)
+		replacedRepositoryURL := strings.Replace(fetched[i].GetRepositoryURL(), API_URL_BASE, URL_BASE, 1)
+		fetched[i].URL = &replacedURL
+		fetched[i].RepositoryURL = &replacedRepositoryURL
 
-		repoURL := issues[i].GetRepositoryURL()
+		repoURL := fetched[i].GetRepositoryURL()
 		owner, repo, _ := config.ParseRepoURL(repoURL)
-		repoKey := owner + "/" + repo
+		repoKey := cacheKey(owner+"/"+repo, itemType, qualifiers)
 
 		// Initialize cache entry if not exists
 		if _, ok := c.cache[repoKey]; !ok {
 			c.cache[repoKey] = make([]*github.Issue, 0)
 		}
-		c.cache[repoKey] = append(c.cache[repoKey], issues[i])
+		c.cache[repoKey] = append(c.cache[repoKey], fetched[i])
 	}
-
-	// Sort by Repository and UpdatedAt
-	sort.Slice(issues, func(i, j int) bool {
-		a, b := issues[i], issues[j]
-		_, aRepo, _ := config.ParseRepoURL(a.GetURL())
-		_, bRepo, _ := config.ParseRepoURL(b.GetURL())
-		switch {
-		case aRepo < bRepo:
-			return true
-		case aRepo > bRepo:
-			return false
-		default:
-			return a.GetUpdatedAt().Time.After(b.GetUpdatedAt().Time)
-		}
-	})
+	issues = append(issues, fetched...)
 
 	return issues, nil
 }
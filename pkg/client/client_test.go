@@ -2,7 +2,7 @@ package client
 
 import (
 	"net/http"
-	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -43,15 +43,8 @@ func TestNewClient(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if tt.token != "" {
-				os.Setenv("GITHUB_TOKEN", tt.token)
-				defer os.Unsetenv("GITHUB_TOKEN")
-			} else {
-				os.Unsetenv("GITHUB_TOKEN")
-			}
-
 			cfg := &config.Config{}
-			client := NewClient(cfg)
+			client := NewClient(cfg, tt.token)
 
 			assert.NotNil(t, client)
 			assert.NotNil(t, client.ghc)
@@ -73,6 +66,7 @@ func TestCheckCache(t *testing.T) {
 		name            string
 		cache           map[string][]*github.Issue
 		ownerRepo       []string
+		qualifiers      string
 		wantIssues      []*github.Issue
 		wantRepoToFetch []string
 	}{
@@ -86,7 +80,7 @@ func TestCheckCache(t *testing.T) {
 		{
 			name: "partial cache hit",
 			cache: map[string][]*github.Issue{
-				"owner/repo1": {
+				"owner/repo1 is:issue": {
 					{Title: github.Ptr("issue1")},
 					{Title: github.Ptr("issue2")},
 				},
@@ -101,8 +95,8 @@ func TestCheckCache(t *testing.T) {
 		{
 			name: "all cache hit",
 			cache: map[string][]*github.Issue{
-				"owner/repo1": {{Title: github.Ptr("issue1")}},
-				"owner/repo2": {{Title: github.Ptr("issue2")}},
+				"owner/repo1 is:issue": {{Title: github.Ptr("issue1")}},
+				"owner/repo2 is:issue": {{Title: github.Ptr("issue2")}},
 			},
 			ownerRepo: []string{"owner/repo1", "owner/repo2"},
 			wantIssues: []*github.Issue{
@@ -111,6 +105,20 @@ func TestCheckCache(t *testing.T) {
 			},
 			wantRepoToFetch: []string{},
 		},
+		{
+			name: "cache is separated by qualifiers",
+			cache: map[string][]*github.Issue{
+				"owner/repo1 is:issue":                      {{Title: github.Ptr("issue1")}},
+				"owner/repo2 is:issue updated:>=2025-01-01": {{Title: github.Ptr("issue2")}},
+				"owner/repo1 is:issue created:>=2025-01-01": {{Title: github.Ptr("issue3")}},
+			},
+			ownerRepo:  []string{"owner/repo1", "owner/repo2"},
+			qualifiers: "updated:>=2025-01-01",
+			wantIssues: []*github.Issue{
+				{Title: github.Ptr("issue2")},
+			},
+			wantRepoToFetch: []string{"owner/repo1"},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -120,7 +128,7 @@ func TestCheckCache(t *testing.T) {
 				cache:  tc.cache,
 			}
 
-			gotIssues, gotRemaining := c.checkCache(tc.ownerRepo)
+			gotIssues, gotRemaining := c.checkCache(tc.ownerRepo, config.ItemTypeIssue, tc.qualifiers)
 			assert.Equal(t, tc.wantIssues, gotIssues)
 			assert.Equal(t, tc.wantRepoToFetch, gotRemaining)
 		})
@@ -235,6 +243,7 @@ func Test_fetchIssuesByRepos(t *testing.T) {
 		name          string
 		ownerRepos    []string
 		labels        []string
+		qualifiers    string
 		mockResponses []mock.MockBackendOption
 		wantErr       bool
 		wantCount     int
@@ -287,6 +296,30 @@ func Test_fetchIssuesByRepos(t *testing.T) {
 			wantErr:   true,
 			wantCount: 0,
 		},
+		{
+			name:       "should add qualifiers to the query",
+			ownerRepos: []string{"owner1/repo1"},
+			labels:     []string{"help-wanted"},
+			qualifiers: "created:>=2025-01-01 updated:>=2025-02-01",
+			mockResponses: []mock.MockBackendOption{
+				mock.WithRequestMatchHandler(
+					mock.GetSearchIssues,
+					http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						q := r.URL.Query().Get("q")
+						if q != `repo:owner1/repo1 is:open is:issue label:"help-wanted" created:>=2025-01-01 updated:>=2025-02-01` {
+							mock.WriteError(w, http.StatusBadRequest, "unexpected query: "+q)
+							return
+						}
+						_, _ = w.Write(mock.MustMarshal(&github.IssuesSearchResult{
+							Total:  github.Ptr(1),
+							Issues: mockIssues[:1],
+						}))
+					}),
+				),
+			},
+			wantErr:   false,
+			wantCount: 1,
+		},
 	}
 
 	for _, tt := range tests {
@@ -302,7 +335,7 @@ func Test_fetchIssuesByRepos(t *testing.T) {
 				cache: make(map[string][]*github.Issue),
 			}
 
-			issues, err := client.fetchIssuesByRepos(tt.ownerRepos)
+			issues, err := client.fetchIssuesByRepos(tt.ownerRepos, config.ItemTypeIssue, tt.qualifiers)
 
 			if tt.wantErr {
 				assert.Error(t, err)
@@ -310,18 +343,87 @@ func Test_fetchIssuesByRepos(t *testing.T) {
 				assert.NoError(t, err)
 				assert.Len(t, issues, tt.wantCount)
 				assert.Len(t, client.cache, len(tt.ownerRepos))
-
-				if tt.wantCount > 0 {
-					// Verify sorting
-					for i := 0; i < len(issues)-1; i++ {
-						_, repo1, _ := config.ParseRepoURL(issues[i].GetURL())
-						_, repo2, _ := config.ParseRepoURL(issues[i+1].GetURL())
-						if repo1 == repo2 {
-							assert.True(t, issues[i].GetUpdatedAt().After(issues[i+1].GetUpdatedAt().Time))
-						}
-					}
-				}
 			}
 		})
 	}
 }
+
+func Test_fetchIssuesByReposPartialCacheHit(t *testing.T) {
+	baseTime := time.Now()
+	cached := createMockIssue(1, "Issue 1", "owner1/repo1", baseTime)
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetSearchIssues,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query().Get("q")
+				if q != `repo:owner2/repo2 is:open is:issue label:"help-wanted"` {
+					mock.WriteError(w, http.StatusBadRequest, "unexpected query: "+q)
+					return
+				}
+				_, _ = w.Write(mock.MustMarshal(&github.IssuesSearchResult{
+					Total:  github.Ptr(1),
+					Issues: []*github.Issue{createMockIssue(2, "Issue 2", "owner2/repo2", baseTime)},
+				}))
+			}),
+		),
+	)
+
+	c := &client{
+		ghc:    github.NewClient(mockedHTTPClient),
+		config: &config.Config{Labels: []string{"help-wanted"}},
+		cache: map[string][]*github.Issue{
+			"owner1/repo1 is:issue": {cached},
+		},
+	}
+
+	issues, err := c.fetchIssuesByRepos([]string{"owner1/repo1", "owner2/repo2"}, config.ItemTypeIssue, "")
+	assert.NoError(t, err)
+	assert.Len(t, issues, 2)
+	// Cached issues must not be cached again
+	assert.Equal(t, []*github.Issue{cached}, c.cache["owner1/repo1 is:issue"])
+	assert.Len(t, c.cache["owner2/repo2 is:issue"], 1)
+}
+
+func TestFetchIssuesWithLabelAliases(t *testing.T) {
+	now := time.Now()
+	var queries []string
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetSearchIssues,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query().Get("q")
+				queries = append(queries, q)
+				repo := "owner/a"
+				if strings.HasPrefix(q, "repo:owner/b") {
+					repo = "owner/b"
+				}
+				_, _ = w.Write(mock.MustMarshal(&github.IssuesSearchResult{
+					Total:  github.Ptr(1),
+					Issues: []*github.Issue{createMockIssue(1, "Issue 1", repo, now)},
+				}))
+			}),
+		),
+	)
+
+	c := &client{
+		ghc: github.NewClient(mockedHTTPClient),
+		config: &config.Config{
+			Repos: map[string][]string{
+				"test": {"https://github.com/owner/a", "https://github.com/owner/b", "https://github.com/owner/c"},
+			},
+			Labels: []string{"good first issue"},
+			LabelAliases: map[string]map[string][]string{
+				"owner/b": {"good first issue": {"E-easy"}},
+			},
+		},
+		cache: make(map[string][]*github.Issue),
+	}
+
+	issues, err := c.FetchIssues()
+	assert.NoError(t, err)
+	assert.Len(t, issues["test"], 2)
+	assert.Equal(t, []string{
+		`repo:owner/a repo:owner/c is:open is:issue label:"good first issue"`,
+		`repo:owner/b is:open is:issue label:"good first issue","E-easy"`,
+	}, queries)
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/ymtdzzz/issue-scouter/pkg/claims"
+)
+
+// fetchComments returns the n most recent comments of the issue, oldest
+// first. total is the number of comments of the issue, which tells the page
+// holding the most recent ones.
+func (c *client) fetchComments(ctx context.Context, owner, repo string, number, total, n int) ([]*github.IssueComment, error) {
+	if n <= 0 || total <= 0 {
+		return nil, nil
+	}
+	key := fmt.Sprintf("%s/%s#%d", owner, repo, number)
+	// Categories may ask for different numbers of comments, a cached fetch
+	// is only reused when it holds enough of them
+	if comments, ok := c.commentCache[key]; ok && len(comments) >= min(n, total) {
+		log.Printf("Cache hit for comments of %s", key)
+		return comments[max(len(comments)-n, 0):], nil
+	}
+
+	// Comments are listed oldest first, the most recent ones are on the last
+	// page and possibly the one before
+	var comments []*github.IssueComment
+	last := (total-1)/n + 1
+	for page := last; page >= 1 && page >= last-1; page-- {
+		cs, _, err := c.ghc.Issues.ListComments(ctx, owner, repo, number, &github.IssueListCommentsOptions{
+			ListOptions: github.ListOptions{Page: page, PerPage: n},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch comments of %s: %w", key, err)
+		}
+		comments = append(cs, comments...)
+		if len(comments) >= n {
+			break
+		}
+	}
+	if len(comments) > n {
+		comments = comments[len(comments)-n:]
+	}
+
+	if c.commentCache == nil {
+		c.commentCache = make(map[string][]*github.IssueComment)
+	}
+	c.commentCache[key] = comments
+	return comments, nil
+}
+
+// Activity summarizes the most recent comments of an issue.
+type Activity struct {
+	// LastCommenter is the login of the author of the last comment.
+	LastCommenter string
+	// MaintainerReplied reports whether a maintainer commented among the
+	// fetched comments.
+	MaintainerReplied bool
+	// LastMaintainerReply is the time of the last comment of a maintainer,
+	// zero when MaintainerReplied is false.
+	LastMaintainerReply time.Time
+	// Claim is the most recent claim found by the detector, nil when the
+	// issue is not likely claimed.
+	Claim *claims.Claim
+}
+
+// maintainerAssociations are the author associations of maintainers.
+var maintainerAssociations = []string{"OWNER", "MEMBER", "COLLABORATOR"}
+
+// IsMaintainer reports whether the author association of a comment is the
+// one of a maintainer of the repository.
+func IsMaintainer(association string) bool {
+	return slices.Contains(maintainerAssociations, association)
+}
+
+// summarizeComments returns the activity of the comments, oldest first.
+func summarizeComments(comments []*github.IssueComment, detector *claims.Detector) *Activity {
+	a := &Activity{}
+	cs := make([]claims.Comment, len(comments))
+	for i, comment := range comments {
+		cs[i] = claims.Comment{
+			Author:     comment.GetUser().GetLogin(),
+			Maintainer: IsMaintainer(comment.GetAuthorAssociation()),
+			Body:       comment.GetBody(),
+			CreatedAt:  comment.GetCreatedAt().Time,
+		}
+		a.LastCommenter = cs[i].Author
+		if cs[i].Maintainer {
+			a.MaintainerReplied = true
+			a.LastMaintainerReply = cs[i].CreatedAt
+		}
+	}
+	a.Claim = detector.Detect(cs)
+	return a
+}
@@ -0,0 +1,192 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/claims"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func TestFetchComments(t *testing.T) {
+	// 7 comments served by pages of per_page comments
+	const total = 7
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposIssuesCommentsByOwnerByRepoByIssueNumber,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+				perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+				var comments []*github.IssueComment
+				for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+					comments = append(comments, &github.IssueComment{Body: github.Ptr(fmt.Sprintf("comment %d", i+1))})
+				}
+				_, _ = w.Write(mock.MustMarshal(comments))
+			}),
+		),
+	)
+
+	tests := []struct {
+		name string
+		n    int
+		want []string
+	}{
+		{"last page is full", 7, []string{"comment 1", "comment 2", "comment 3", "comment 4", "comment 5", "comment 6", "comment 7"}},
+		{"last page is partial", 3, []string{"comment 5", "comment 6", "comment 7"}},
+		{"more than total", 10, []string{"comment 1", "comment 2", "comment 3", "comment 4", "comment 5", "comment 6", "comment 7"}},
+		{"disabled", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &client{ghc: github.NewClient(mockedHTTPClient), config: &config.Config{}}
+			comments, err := c.fetchComments(context.Background(), "owner", "repo", 1, total, tt.n)
+			assert.NoError(t, err)
+
+			var got []string
+			for _, comment := range comments {
+				got = append(got, comment.GetBody())
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFetchCommentsCachedWithDifferentCounts(t *testing.T) {
+	const total = 7
+	requests := 0
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposIssuesCommentsByOwnerByRepoByIssueNumber,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+				perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+				var comments []*github.IssueComment
+				for i := (page - 1) * perPage; i < page*perPage && i < total; i++ {
+					comments = append(comments, &github.IssueComment{Body: github.Ptr(fmt.Sprintf("comment %d", i+1))})
+				}
+				_, _ = w.Write(mock.MustMarshal(comments))
+			}),
+		),
+	)
+	c := &client{ghc: github.NewClient(mockedHTTPClient), config: &config.Config{}}
+	fetch := func(n int) []string {
+		t.Helper()
+		comments, err := c.fetchComments(context.Background(), "owner", "repo", 1, total, n)
+		assert.NoError(t, err)
+		var got []string
+		for _, comment := range comments {
+			got = append(got, comment.GetBody())
+		}
+		return got
+	}
+
+	// A category with fewer comments must not limit the next one
+	assert.Equal(t, []string{"comment 6", "comment 7"}, fetch(2))
+	assert.Equal(t, []string{"comment 3", "comment 4", "comment 5", "comment 6", "comment 7"}, fetch(5))
+	fetched := requests
+
+	// Fewer comments are taken from the cache
+	assert.Equal(t, []string{"comment 7"}, fetch(1))
+	assert.Equal(t, []string{"comment 3", "comment 4", "comment 5", "comment 6", "comment 7"}, fetch(5))
+	assert.Equal(t, fetched, requests)
+}
+
+func TestSummarizeComments(t *testing.T) {
+	replyTime := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	comment := func(login, association, body string) *github.IssueComment {
+		return &github.IssueComment{
+			User:              &github.User{Login: github.Ptr(login)},
+			AuthorAssociation: github.Ptr(association),
+			Body:              github.Ptr(body),
+			CreatedAt:         &github.Timestamp{Time: replyTime},
+		}
+	}
+
+	tests := []struct {
+		name     string
+		comments []*github.IssueComment
+		want     *Activity
+	}{
+		{
+			name: "no comments",
+			want: &Activity{},
+		},
+		{
+			name: "maintainer replied",
+			comments: []*github.IssueComment{
+				comment("user", "NONE", "Is this still relevant?"),
+				comment("owner", "MEMBER", "Yes"),
+			},
+			want: &Activity{LastCommenter: "owner", MaintainerReplied: true, LastMaintainerReply: replyTime},
+		},
+		{
+			name: "claimed",
+			comments: []*github.IssueComment{
+				comment("user", "CONTRIBUTOR", "Hi! I'd like to work on this."),
+				comment("owner", "COLLABORATOR", "Sure, go ahead"),
+			},
+			want: &Activity{
+				LastCommenter:       "owner",
+				MaintainerReplied:   true,
+				LastMaintainerReply: replyTime,
+				Claim:               &claims.Claim{Claimer: "user", At: replyTime, Acknowledged: true},
+			},
+		},
+	}
+
+	detector, err := claims.New(config.Claims{}, replyTime)
+	assert.NoError(t, err)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, summarizeComments(tt.comments, detector))
+		})
+	}
+}
+
+func TestFetchIssuesExcludeClaimed(t *testing.T) {
+	now := time.Now()
+	claimed := createMockIssue(1, "Claimed", "owner/repo", now)
+	claimed.Comments = github.Ptr(1)
+	open := createMockIssue(2, "Open", "owner/repo", now)
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetSearchIssues,
+			&github.IssuesSearchResult{Total: github.Ptr(2), Issues: []*github.Issue{claimed, open}},
+		),
+		mock.WithRequestMatch(
+			mock.GetReposIssuesCommentsByOwnerByRepoByIssueNumber,
+			[]*github.IssueComment{{
+				User:              &github.User{Login: github.Ptr("user")},
+				AuthorAssociation: github.Ptr("NONE"),
+				Body:              github.Ptr("I would like to work on this"),
+			}},
+		),
+	)
+
+	excludeClaimed := true
+	c := &client{
+		ghc: github.NewClient(mockedHTTPClient),
+		config: &config.Config{
+			Repos:           map[string][]string{"test": {"https://github.com/owner/repo"}},
+			Labels:          []string{"good first issue"},
+			Filters:         config.Filters{ExcludeClaimed: &excludeClaimed},
+			CommentActivity: config.CommentActivity{MaxComments: 10},
+		},
+		cache: make(map[string][]*github.Issue),
+	}
+
+	issues, err := c.FetchIssues()
+	assert.NoError(t, err)
+	assert.Len(t, issues["test"], 1)
+	assert.Equal(t, "Open", issues["test"][0].GetTitle())
+	assert.Equal(t, &Activity{}, issues["test"][0].Activity)
+}
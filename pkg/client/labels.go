@@ -0,0 +1,55 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/google/go-github/v69/github"
+)
+
+// ListLabels returns all the labels of the repository, following pagination.
+func (c *client) ListLabels(ctx context.Context, owner, repo string) ([]*github.Label, error) {
+	var all []*github.Label
+	opts := &github.ListOptions{PerPage: 100}
+	for {
+		labels, resp, err := c.ghc.Issues.ListLabels(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch labels of %s/%s: %w", owner, repo, err)
+		}
+		all = append(all, labels...)
+		if resp.NextPage == 0 {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// CountOpenIssues returns the number of open issues, pull requests excluded,
+// having each label of the repository. Labels are keyed by lower-cased name
+// as label names are case-insensitive.
+func (c *client) CountOpenIssues(ctx context.Context, owner, repo string) (map[string]int, error) {
+	counts := make(map[string]int)
+	opts := &github.IssueListByRepoOptions{
+		State:       "open",
+		ListOptions: github.ListOptions{PerPage: 100},
+	}
+	for {
+		issues, resp, err := c.ghc.Issues.ListByRepo(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch open issues of %s/%s: %w", owner, repo, err)
+		}
+		for _, issue := range issues {
+			if issue.IsPullRequest() {
+				continue
+			}
+			for _, l := range issue.Labels {
+				counts[strings.ToLower(l.GetName())]++
+			}
+		}
+		if resp.NextPage == 0 {
+			return counts, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func TestListLabels(t *testing.T) {
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposLabelsByOwnerByRepo,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				page := r.URL.Query().Get("page")
+				if page == "" {
+					page = "1"
+				}
+				if page == "1" {
+					w.Header().Set("Link", `<https://api.github.com/repos/owner/repo/labels?page=2>; rel="next"`)
+				}
+				_, _ = w.Write(mock.MustMarshal([]*github.Label{
+					{Name: github.Ptr(fmt.Sprintf("label %s", page))},
+				}))
+			}),
+		),
+	)
+	c := &client{ghc: github.NewClient(mockedHTTPClient), config: &config.Config{}}
+
+	labels, err := c.ListLabels(context.Background(), "owner", "repo")
+	assert.NoError(t, err)
+	assert.Len(t, labels, 2)
+	assert.Equal(t, "label 1", labels[0].GetName())
+	assert.Equal(t, "label 2", labels[1].GetName())
+}
+
+func TestCountOpenIssues(t *testing.T) {
+	issue := func(labels ...string) *github.Issue {
+		i := &github.Issue{}
+		for _, l := range labels {
+			i.Labels = append(i.Labels, &github.Label{Name: github.Ptr(l)})
+		}
+		return i
+	}
+	pr := issue("help wanted")
+	pr.PullRequestLinks = &github.PullRequestLinks{}
+
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchPages(
+			mock.GetReposIssuesByOwnerByRepo,
+			[]*github.Issue{issue("bug", "help wanted"), issue("Help Wanted")},
+			[]*github.Issue{issue("bug"), pr},
+		),
+	)
+	c := &client{ghc: github.NewClient(mockedHTTPClient), config: &config.Config{}}
+
+	counts, err := c.CountOpenIssues(context.Background(), "owner", "repo")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]int{"bug": 2, "help wanted": 2}, counts)
+}
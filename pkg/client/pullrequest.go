@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/google/go-github/v69/github"
+)
+
+const (
+	ReviewApproved         = "approved"
+	ReviewChangesRequested = "changes requested"
+	ReviewRequired         = "review required"
+)
+
+// PullRequest holds the state of a pull request missing from search results.
+type PullRequest struct {
+	Draft bool
+	// Mergeable is nil while GitHub is computing it.
+	Mergeable *bool
+	// ReviewStatus is one of the Review constants, or empty without any review
+	// nor requested reviewer.
+	ReviewStatus string
+}
+
+func (c *client) fetchPullRequest(ctx context.Context, owner, repo string, number int) (*PullRequest, error) {
+	key := fmt.Sprintf("%s/%s#%d", owner, repo, number)
+	if pr, ok := c.prCache[key]; ok {
+		log.Printf("Cache hit for pull request %s", key)
+		return pr, nil
+	}
+
+	gpr, _, err := c.ghc.PullRequests.Get(ctx, owner, repo, number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pull request %s: %w", key, err)
+	}
+	reviews, _, err := c.ghc.PullRequests.ListReviews(ctx, owner, repo, number, &github.ListOptions{PerPage: 100})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch reviews of pull request %s: %w", key, err)
+	}
+
+	pr := &PullRequest{
+		Draft:        gpr.GetDraft(),
+		Mergeable:    gpr.Mergeable,
+		ReviewStatus: reviewStatus(reviews, len(gpr.RequestedReviewers)+len(gpr.RequestedTeams)),
+	}
+	if c.prCache == nil {
+		c.prCache = make(map[string]*PullRequest)
+	}
+	c.prCache[key] = pr
+	return pr, nil
+}
+
+// reviewStatus sums up the latest review of each reviewer. Comments do not
+// change the state of a previous review.
+func reviewStatus(reviews []*github.PullRequestReview, requested int) string {
+	latest := make(map[string]string)
+	for _, r := range reviews {
+		switch state := r.GetState(); state {
+		case "APPROVED", "CHANGES_REQUESTED", "DISMISSED":
+			latest[r.GetUser().GetLogin()] = state
+		}
+	}
+
+	approved := false
+	for _, state := range latest {
+		switch state {
+		case "CHANGES_REQUESTED":
+			return ReviewChangesRequested
+		case "APPROVED":
+			approved = true
+		}
+	}
+	switch {
+	case approved:
+		return ReviewApproved
+	case requested > 0:
+		return ReviewRequired
+	}
+	return ""
+}
@@ -0,0 +1,119 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func TestReviewStatus(t *testing.T) {
+	review := func(login, state string) *github.PullRequestReview {
+		return &github.PullRequestReview{User: &github.User{Login: github.Ptr(login)}, State: github.Ptr(state)}
+	}
+
+	tests := []struct {
+		name      string
+		reviews   []*github.PullRequestReview
+		requested int
+		want      string
+	}{
+		{
+			name: "no reviews",
+			want: "",
+		},
+		{
+			name:      "requested reviewers",
+			requested: 1,
+			want:      ReviewRequired,
+		},
+		{
+			name:    "approved",
+			reviews: []*github.PullRequestReview{review("a", "APPROVED"), review("b", "COMMENTED")},
+			want:    ReviewApproved,
+		},
+		{
+			name:    "changes requested by one reviewer",
+			reviews: []*github.PullRequestReview{review("a", "APPROVED"), review("b", "CHANGES_REQUESTED")},
+			want:    ReviewChangesRequested,
+		},
+		{
+			name:    "changes addressed",
+			reviews: []*github.PullRequestReview{review("a", "CHANGES_REQUESTED"), review("a", "COMMENTED"), review("a", "APPROVED")},
+			want:    ReviewApproved,
+		},
+		{
+			name:      "dismissed review",
+			reviews:   []*github.PullRequestReview{review("a", "CHANGES_REQUESTED"), review("a", "DISMISSED")},
+			requested: 1,
+			want:      ReviewRequired,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, reviewStatus(tt.reviews, tt.requested))
+		})
+	}
+}
+
+func TestFetchIssuesWithPullRequests(t *testing.T) {
+	now := time.Now()
+	pr := createMockIssue(2, "PR 2", "owner/repo", now)
+	pr.PullRequestLinks = &github.PullRequestLinks{}
+
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetSearchIssues,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query().Get("q")
+				result := &github.IssuesSearchResult{}
+				switch {
+				case strings.Contains(q, "is:issue"):
+					result.Issues = []*github.Issue{createMockIssue(1, "Issue 1", "owner/repo", now)}
+				case strings.Contains(q, "is:pr"):
+					result.Issues = []*github.Issue{pr}
+				}
+				result.Total = github.Ptr(len(result.Issues))
+				_, _ = w.Write(mock.MustMarshal(result))
+			}),
+		),
+		mock.WithRequestMatch(
+			mock.GetReposPullsByOwnerByRepoByPullNumber,
+			&github.PullRequest{
+				Draft:              github.Ptr(true),
+				Mergeable:          github.Ptr(false),
+				RequestedReviewers: []*github.User{{Login: github.Ptr("maintainer")}},
+			},
+		),
+		mock.WithRequestMatch(
+			mock.GetReposPullsReviewsByOwnerByRepoByPullNumber,
+			[]*github.PullRequestReview{},
+		),
+	)
+
+	c := &client{
+		ghc: github.NewClient(mockedHTTPClient),
+		config: &config.Config{
+			Repos:     map[string][]string{"test": {"https://github.com/owner/repo"}},
+			Labels:    []string{"help wanted"},
+			ItemTypes: []string{config.ItemTypeIssue, config.ItemTypePullRequest},
+		},
+		cache: make(map[string][]*github.Issue),
+	}
+
+	issues, err := c.FetchIssues()
+	assert.NoError(t, err)
+	assert.Len(t, issues["test"], 2)
+	assert.Nil(t, issues["test"][0].PullRequest)
+	assert.Equal(t, &PullRequest{
+		Draft:        true,
+		Mergeable:    github.Ptr(false),
+		ReviewStatus: ReviewRequired,
+	}, issues["test"][1].PullRequest)
+}
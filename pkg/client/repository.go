@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+// Repository is the health metadata of a repository.
+type Repository struct {
+	Owner           string
+	Name            string
+	Description     string
+	Stars           int
+	Archived        bool
+	PushedAt        time.Time
+	OpenIssues      int
+	Language        string
+	License         string
+	HasContributing bool
+	// Languages is the number of bytes of code per language. It is only
+	// fetched when filtering by language.
+	Languages map[string]int
+}
+
+// languageShareThreshold is the share of the code a language needs to match
+// the languages filter when it is not the primary language.
+const languageShareThreshold = 0.1
+
+// HasLanguage reports whether the repository is written in one of langs,
+// compared case-insensitively.
+func (r *Repository) HasLanguage(langs []string) bool {
+	total := 0
+	for _, n := range r.Languages {
+		total += n
+	}
+	for _, l := range langs {
+		if strings.EqualFold(l, r.Language) {
+			return true
+		}
+		for name, n := range r.Languages {
+			if strings.EqualFold(l, name) && total > 0 && float64(n)/float64(total) >= languageShareThreshold {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r *Repository) FullName() string {
+	return r.Owner + "/" + r.Name
+}
+
+func (r *Repository) URL() string {
+	return fmt.Sprintf("https://%s/%s/%s", URL_BASE, r.Owner, r.Name)
+}
+
+// CategoryRepository is a repository configured in a category. SkipReason
+// is set when the repository was dropped by the filters of the category.
+type CategoryRepository struct {
+	*Repository
+	SkipReason string
+}
+
+// Repositories holds the repositories of each category, in configured order.
+type Repositories map[string][]*CategoryRepository
+
+// Repositories returns the repositories seen by the last FetchIssues. The
+// metadata is only fetched when needed by the config, otherwise only the
+// owner and name are set.
+func (c *client) Repositories() Repositories {
+	return c.categoryRepos
+}
+
+func (c *client) fetchRepository(ctx context.Context, owner, name string, withLanguages bool) (*Repository, error) {
+	key := owner + "/" + name
+	if r, ok := c.repoCache[key]; ok {
+		log.Printf("Cache hit for repository %s", key)
+		if withLanguages && r.Languages == nil {
+			c.fetchLanguages(ctx, r)
+		}
+		return r, nil
+	}
+
+	gr, _, err := c.ghc.Repositories.Get(ctx, owner, name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch repository %s: %w", key, err)
+	}
+	r := &Repository{
+		Owner:       owner,
+		Name:        name,
+		Description: gr.GetDescription(),
+		Stars:       gr.GetStargazersCount(),
+		Archived:    gr.GetArchived(),
+		PushedAt:    gr.GetPushedAt().Time,
+		OpenIssues:  gr.GetOpenIssuesCount(),
+		Language:    gr.GetLanguage(),
+		License:     gr.GetLicense().GetSPDXID(),
+	}
+
+	metrics, _, err := c.ghc.Repositories.GetCommunityHealthMetrics(ctx, owner, name)
+	if err != nil {
+		log.Printf("Failed to fetch community profile of %s: %v", key, err)
+	} else {
+		r.HasContributing = metrics.GetFiles().GetContributing() != nil
+	}
+
+	if withLanguages {
+		c.fetchLanguages(ctx, r)
+	}
+
+	if c.repoCache == nil {
+		c.repoCache = make(map[string]*Repository)
+	}
+	c.repoCache[key] = r
+	return r, nil
+}
+
+// fetchLanguages sets the language breakdown of r. On failure, r is left
+// with the primary language only.
+func (c *client) fetchLanguages(ctx context.Context, r *Repository) {
+	langs, _, err := c.ghc.Repositories.ListLanguages(ctx, r.Owner, r.Name)
+	if err != nil {
+		log.Printf("Failed to fetch languages of %s: %v", r.FullName(), err)
+		r.Languages = map[string]int{}
+		return
+	}
+	r.Languages = langs
+}
+
+// skipReason returns why the repository is dropped by the filters, or an
+// empty string if it is kept.
+func skipReason(r *Repository, f config.Filters, now time.Time) string {
+	switch {
+	case f.SkipArchived != nil && *f.SkipArchived && r.Archived:
+		return "archived"
+	case f.MinStars != nil && r.Stars < *f.MinStars:
+		return fmt.Sprintf("fewer than %d stars", *f.MinStars)
+	case f.ActiveWithin != nil && *f.ActiveWithin > 0 && now.Sub(r.PushedAt) > time.Duration(*f.ActiveWithin):
+		return fmt.Sprintf("no push within %s", *f.ActiveWithin)
+	case len(f.Languages) > 0 && !r.HasLanguage(f.Languages):
+		return fmt.Sprintf("not written in %s", strings.Join(f.Languages, ", "))
+	}
+	return ""
+}
+
+func newRepository(owner, name string) *Repository {
+	return &Repository{Owner: owner, Name: name}
+}
@@ -0,0 +1,268 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/migueleliasweb/go-github-mock/src/mock"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func TestSkipReason(t *testing.T) {
+	now := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	repo := &Repository{
+		Owner:    "owner",
+		Name:     "repo",
+		Stars:    50,
+		Archived: true,
+		PushedAt: now.AddDate(0, -3, 0),
+		Language: "Go",
+		Languages: map[string]int{
+			"Go":       800,
+			"Shell":    150,
+			"Makefile": 50,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		filters config.Filters
+		want    string
+	}{
+		{
+			name:    "no filters",
+			filters: config.Filters{},
+			want:    "",
+		},
+		{
+			name:    "archived",
+			filters: config.Filters{SkipArchived: github.Ptr(true)},
+			want:    "archived",
+		},
+		{
+			name:    "archived allowed",
+			filters: config.Filters{SkipArchived: github.Ptr(false)},
+			want:    "",
+		},
+		{
+			name:    "min stars",
+			filters: config.Filters{MinStars: github.Ptr(100)},
+			want:    "fewer than 100 stars",
+		},
+		{
+			name:    "enough stars",
+			filters: config.Filters{MinStars: github.Ptr(50)},
+			want:    "",
+		},
+		{
+			name:    "inactive",
+			filters: config.Filters{ActiveWithin: github.Ptr(config.Duration(config.Month))},
+			want:    "no push within 1mo",
+		},
+		{
+			name:    "active",
+			filters: config.Filters{ActiveWithin: github.Ptr(config.Duration(config.Year))},
+			want:    "",
+		},
+		{
+			name:    "primary language",
+			filters: config.Filters{Languages: []string{"rust", "go"}},
+			want:    "",
+		},
+		{
+			name:    "significant language share",
+			filters: config.Filters{Languages: []string{"Shell"}},
+			want:    "",
+		},
+		{
+			name:    "insignificant language share",
+			filters: config.Filters{Languages: []string{"Makefile"}},
+			want:    "not written in Makefile",
+		},
+		{
+			name:    "other language",
+			filters: config.Filters{Languages: []string{"Rust", "Python"}},
+			want:    "not written in Rust, Python",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, skipReason(repo, tt.filters, now))
+		})
+	}
+}
+
+func TestFetchRepository(t *testing.T) {
+	pushedAt := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposByOwnerByRepo,
+			&github.Repository{
+				Description:     github.Ptr("A repository"),
+				StargazersCount: github.Ptr(120),
+				PushedAt:        &github.Timestamp{Time: pushedAt},
+				OpenIssuesCount: github.Ptr(7),
+				Language:        github.Ptr("Go"),
+				License:         &github.License{SPDXID: github.Ptr("MIT")},
+			},
+		),
+		mock.WithRequestMatch(
+			mock.GetReposCommunityProfileByOwnerByRepo,
+			&github.CommunityHealthMetrics{
+				Files: &github.CommunityHealthFiles{
+					Contributing: &github.Metric{Name: github.Ptr("CONTRIBUTING.md")},
+				},
+			},
+		),
+		mock.WithRequestMatch(
+			mock.GetReposLanguagesByOwnerByRepo,
+			map[string]int{"Go": 900, "Shell": 100},
+		),
+	)
+	c := &client{ghc: github.NewClient(mockedHTTPClient), config: &config.Config{}}
+
+	want := &Repository{
+		Owner:           "owner",
+		Name:            "repo",
+		Description:     "A repository",
+		Stars:           120,
+		PushedAt:        pushedAt,
+		OpenIssues:      7,
+		Language:        "Go",
+		License:         "MIT",
+		HasContributing: true,
+	}
+	got, err := c.fetchRepository(context.Background(), "owner", "repo", false)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// The second call is served from the cache, the mock has no more responses
+	got, err = c.fetchRepository(context.Background(), "owner", "repo", false)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+
+	// The language breakdown is fetched once needed, even for cached repositories
+	want.Languages = map[string]int{"Go": 900, "Shell": 100}
+	got, err = c.fetchRepository(context.Background(), "owner", "repo", true)
+	assert.NoError(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestFetchIssuesWithRepositoryFilters(t *testing.T) {
+	now := time.Now()
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatchHandler(
+			mock.GetReposByOwnerByRepo,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				repo := &github.Repository{
+					StargazersCount: github.Ptr(10),
+					PushedAt:        &github.Timestamp{Time: now},
+				}
+				if r.URL.Path == "/repos/owner/archived" {
+					repo.Archived = github.Ptr(true)
+				}
+				_, _ = w.Write(mock.MustMarshal(repo))
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposCommunityProfileByOwnerByRepo,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mock.WriteError(w, http.StatusNotFound, "not found")
+			}),
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetSearchIssues,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				q := r.URL.Query().Get("q")
+				if q != `repo:owner/active is:open is:issue label:"help wanted"` {
+					mock.WriteError(w, http.StatusBadRequest, "unexpected query: "+q)
+					return
+				}
+				_, _ = w.Write(mock.MustMarshal(&github.IssuesSearchResult{
+					Total:  github.Ptr(1),
+					Issues: []*github.Issue{createMockIssue(1, "Issue 1", "owner/active", now)},
+				}))
+			}),
+		),
+	)
+
+	c := &client{
+		ghc: github.NewClient(mockedHTTPClient),
+		config: &config.Config{
+			Repos: map[string][]string{
+				"test": {"https://github.com/owner/archived", "https://github.com/owner/active"},
+			},
+			Labels:  []string{"help wanted"},
+			Filters: config.Filters{SkipArchived: github.Ptr(true)},
+		},
+		cache: make(map[string][]*github.Issue),
+	}
+
+	issues, err := c.FetchIssues()
+	assert.NoError(t, err)
+	assert.Len(t, issues["test"], 1)
+	assert.Equal(t, "owner/active", issues["test"][0].Repo.FullName())
+
+	repos := c.Repositories()["test"]
+	assert.Len(t, repos, 2)
+	assert.Equal(t, "owner/archived", repos[0].FullName())
+	assert.Equal(t, "archived", repos[0].SkipReason)
+	assert.Equal(t, "owner/active", repos[1].FullName())
+	assert.Equal(t, "", repos[1].SkipReason)
+}
+
+func TestFetchIssuesWithLLMExport(t *testing.T) {
+	now := time.Now()
+	mockedHTTPClient := mock.NewMockedHTTPClient(
+		mock.WithRequestMatch(
+			mock.GetReposByOwnerByRepo,
+			&github.Repository{
+				Description:     github.Ptr("A repository"),
+				Language:        github.Ptr("Go"),
+				StargazersCount: github.Ptr(42),
+				License:         &github.License{SPDXID: github.Ptr("MIT")},
+				PushedAt:        &github.Timestamp{Time: now},
+			},
+		),
+		mock.WithRequestMatchHandler(
+			mock.GetReposCommunityProfileByOwnerByRepo,
+			http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				mock.WriteError(w, http.StatusNotFound, "not found")
+			}),
+		),
+		mock.WithRequestMatch(
+			mock.GetSearchIssues,
+			&github.IssuesSearchResult{
+				Total:  github.Ptr(1),
+				Issues: []*github.Issue{createMockIssue(1, "Issue 1", "owner/repo", now)},
+			},
+		),
+	)
+
+	// The export is the only feature needing the metadata
+	c := &client{
+		ghc: github.NewClient(mockedHTTPClient),
+		config: &config.Config{
+			Repos:  map[string][]string{"test": {"https://github.com/owner/repo"}},
+			Labels: []string{"help wanted"},
+			LLM:    config.LLM{Enabled: true},
+		},
+		cache: make(map[string][]*github.Issue),
+	}
+
+	issues, err := c.FetchIssues()
+	assert.NoError(t, err)
+	assert.Len(t, issues["test"], 1)
+	repo := issues["test"][0].Repo
+	assert.NotNil(t, repo)
+	assert.Equal(t, "owner/repo", repo.FullName())
+	assert.Equal(t, "A repository", repo.Description)
+	assert.Equal(t, "Go", repo.Language)
+	assert.Equal(t, 42, repo.Stars)
+	assert.Equal(t, "MIT", repo.License)
+}
@@ -1,46 +1,466 @@
 package config
 
 import (
+	"errors"
 	"fmt"
-	"os"
-	"path/filepath"
+	"reflect"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/creasty/defaults"
 	"gopkg.in/yaml.v3"
 )
 
+// Config is the configuration file. The description tags are published in
+// the JSON Schema, see JSONSchema.
 type Config struct {
-	Repos           map[string][]string `yaml:"repositories"`
-	Labels          []string            `yaml:"labels" default:"[\"good first issue\"]"`
-	PerPage         int                 `yaml:"per_page" default:"100"`
-	Destination     string              `yaml:"destination" default:"."`
-	Description     string              `yaml:"description" default:"This file is generated by [issue-scouter](https://github.com/ymtdzzz/issue-scouter)"`
-	IncludeMetadata bool                `yaml:"include_metadata" default:"false"`
+	Repos            map[string][]string            `yaml:"repositories" schema:"required" description:"Repositories to search, grouped by category. Each category is rendered as its own page."`
+	Labels           []string                       `yaml:"labels" default:"[\"good first issue\"]" description:"Issues having any of these labels are listed."`
+	LabelAliases     map[string]map[string][]string `yaml:"label_aliases" description:"Other names of the labels in some repositories, keyed by owner/name and then by label. Run 'issue-scouter labels --emit aliases' to generate them."`
+	PerPage          int                            `yaml:"per_page" default:"100" schema:"minimum=1,maximum=100" description:"Number of search results fetched per request."`
+	Destination      string                         `yaml:"destination" default:"." description:"Directory, relative to the working directory, where the issue list is written."`
+	Description      string                         `yaml:"description" default:"This file is generated by [issue-scouter](https://github.com/ymtdzzz/issue-scouter)" description:"Text shown at the top of the generated index page."`
+	IncludeMetadata  bool                           `yaml:"include_metadata" default:"false" description:"Embed detailed issue metadata as JSON comments in the generated pages."`
+	Scoring          Scoring                        `yaml:"scoring" description:"Ranking of issues by how suitable they are for new contributors."`
+	LLM              LLM                            `yaml:"llm" description:"Export of the issues as JSON Lines files and a prompt template to feed a language model."`
+	SQLite           SQLite                         `yaml:"sqlite" description:"Export of every run to a SQLite database, to analyse the issues over time."`
+	Stats            Stats                          `yaml:"stats" description:"Page of trends computed from the SQLite run history."`
+	Excerpt          Excerpt                        `yaml:"excerpt" description:"Excerpt of the issue description shown in the tables."`
+	CommentActivity  CommentActivity                `yaml:"comment_activity" description:"Fetch the most recent comments of each issue to show the last commenter, maintainer replies and claims."`
+	Claims           Claims                         `yaml:"claims" description:"Rules telling which issues are likely claimed by someone in their comments."`
+	Layout           string                         `yaml:"layout" default:"flat" schema:"enum=flat|grouped" description:"Layout of the category pages: a single table, or a table of contents and a section per repository with its description, stars and issue count."`
+	MaxIssuesPerPage int                            `yaml:"max_issues_per_page" default:"0" schema:"minimum=0" description:"Split the category pages with more items than this into issues/<category>/page-1.md, page-2.md, ... linked to each other. Disabled when 0."`
+	Views            []string                       `yaml:"views" default:"[\"category\"]" schema:"enum=category|language" description:"How issues are grouped into pages: per configured category and/or per primary language of the repository. The index links every view."`
+	ItemTypes        []string                       `yaml:"item_types" default:"[\"issue\"]" schema:"enum=issue|pr" description:"Kinds of items to search with the labels: issues and/or pull requests. Pull requests are listed in their own table."`
+	Sort             []SortKey                      `yaml:"sort" description:"Order of the tables, by the first field and then by the next ones on ties. Defaults to the repository and the last update, most recent first, preceded by the score when scoring is enabled."`
+	Duplicates       string                         `yaml:"duplicates" default:"allow" schema:"enum=allow|first-category-wins|cross-reference" description:"What to do with issues found in several categories: list them in every category, only in the first category in alphabetical order, or in every category with links to the others."`
+	RepoSummary      bool                           `yaml:"repository_summary" default:"false" description:"Add a table of repository health metadata (stars, last push, license, ...) to each category page."`
+	StaleAfter       Duration                       `yaml:"stale_after" description:"Mark issues without any update for this long as stale in an extra column, e.g. 180d. Disabled when empty."`
+	Categories       map[string]Category            `yaml:"categories" description:"Settings overriding the global ones for each category of repositories."`
+	Filters          `yaml:",inline"`
+
+	// Include lists additional configuration files. It is resolved and
+	// removed while loading, so it is always empty after LoadConfig.
+	Include []string `yaml:"include" description:"Configuration files merged into this one, relative to this file. Glob patterns are allowed."`
+
+	// node is the merged YAML document and sources maps its nodes to the
+	// files they were read from, used to locate validation errors.
+	node    *yaml.Node
+	sources map[*yaml.Node]string
+}
+
+// Filters narrows down the issues searched. They can be set globally and
+// overridden for each category.
+type Filters struct {
+	MaxAge         *Duration `yaml:"max_age" description:"Skip issues created longer ago than this, e.g. 1y."`
+	MinAge         *Duration `yaml:"min_age" description:"Skip issues created more recently than this, e.g. 7d to leave time for triage."`
+	UpdatedWithin  *Duration `yaml:"updated_within" description:"Only list issues updated within this period, e.g. 6mo."`
+	CreatedWithin  *Duration `yaml:"created_within" description:"Only list issues created within this period. Combined with max_age, the shorter one applies."`
+	SkipArchived   *bool     `yaml:"skip_archived" description:"Skip archived repositories."`
+	MinStars       *int      `yaml:"min_stars" schema:"minimum=0" description:"Skip repositories with fewer stars than this."`
+	ActiveWithin   *Duration `yaml:"active_within" description:"Skip repositories without any push within this period, e.g. 1y."`
+	Languages      []string  `yaml:"languages" description:"Only search repositories written in one of these languages, by primary language or a significant share of the code."`
+	MinReactions   *int      `yaml:"min_reactions" schema:"minimum=0" description:"Skip issues with fewer reactions than this, all kinds of reactions included."`
+	ExcludeClaimed *bool     `yaml:"exclude_claimed" description:"Skip issues where someone asked to work on it in the most recent comments. Fetches comments even when comment_activity is disabled."`
+}
+
+const (
+	ViewCategory = "category"
+	ViewLanguage = "language"
+)
+
+const (
+	LayoutFlat    = "flat"
+	LayoutGrouped = "grouped"
+)
+
+const (
+	ItemTypeIssue       = "issue"
+	ItemTypePullRequest = "pr"
+)
+
+// Types returns the item types to search. Without any type, only issues are
+// searched.
+func (c *Config) Types() []string {
+	if len(c.ItemTypes) == 0 {
+		return []string{ItemTypeIssue}
+	}
+	return c.ItemTypes
+}
+
+// HasItemType reports whether items of the type are searched.
+func (c *Config) HasItemType(itemType string) bool {
+	return slices.Contains(c.Types(), itemType)
 }
 
-func (c *Config) LabelsForQuery() string {
-	labels := make([]string, len(c.Labels))
-	for i, label := range c.Labels {
-		labels[i] = "\"" + label + "\""
+const (
+	SortRepo      = "repo"
+	SortCreated   = "created"
+	SortUpdated   = "updated"
+	SortComments  = "comments"
+	SortReactions = "reactions"
+	SortScore     = "score"
+
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
+// SortKey is a field the tables are sorted by.
+type SortKey struct {
+	Field string `yaml:"field" schema:"required,enum=repo|created|updated|comments|reactions|score" description:"Field to sort by."`
+	Order string `yaml:"order" schema:"enum=asc|desc" description:"Ascending or descending order. Defaults to asc for repo and desc for the other fields."`
+}
+
+// IsDescending reports whether the key sorts in descending order.
+func (k SortKey) IsDescending() bool {
+	if k.Order == "" {
+		return k.Field != SortRepo
 	}
-	return strings.Join(labels, ",")
+	return k.Order == OrderDesc
 }
 
-func LoadConfig(filename string) (*Config, error) {
-	filename = filepath.Clean(filename)
-	data, err := os.ReadFile(filename)
+// SortKeys returns the keys the tables are sorted by.
+func (c *Config) SortKeys() []SortKey {
+	if len(c.Sort) > 0 {
+		return c.Sort
+	}
+	keys := []SortKey{{Field: SortRepo, Order: OrderAsc}, {Field: SortUpdated, Order: OrderDesc}}
+	if c.Scoring.Enabled {
+		keys = append([]SortKey{{Field: SortScore, Order: OrderDesc}}, keys...)
+	}
+	return keys
+}
+
+const (
+	DuplicatesAllow             = "allow"
+	DuplicatesFirstCategoryWins = "first-category-wins"
+	DuplicatesCrossReference    = "cross-reference"
+)
+
+// HasView reports whether the view is enabled. Without any view, issues are
+// grouped by category.
+func (c *Config) HasView(view string) bool {
+	if len(c.Views) == 0 {
+		return view == ViewCategory
+	}
+	return slices.Contains(c.Views, view)
+}
+
+// NeedsRepositories reports whether the metadata of the repositories in the
+// category has to be fetched. The LLM export includes it in every record.
+func (c *Config) NeedsRepositories(category string) bool {
+	return c.RepoSummary || c.LLM.Enabled || c.Layout == LayoutGrouped || c.HasView(ViewLanguage) || c.FiltersFor(category).FiltersRepositories()
+}
+
+// FiltersRepositories reports whether the filters need the metadata of the
+// repositories.
+func (f Filters) FiltersRepositories() bool {
+	return f.SkipArchived != nil && *f.SkipArchived || f.MinStars != nil && *f.MinStars > 0 ||
+		f.ActiveWithin != nil && *f.ActiveWithin > 0 || len(f.Languages) > 0
+}
+
+// validAges reports whether min_age is shorter than max_age, when both are
+// set.
+func (f Filters) validAges() bool {
+	return f.MinAge == nil || f.MaxAge == nil || *f.MinAge <= 0 || *f.MaxAge <= 0 || *f.MinAge < *f.MaxAge
+}
+
+// Category holds the settings of a single category.
+type Category struct {
+	Filters `yaml:",inline"`
+}
+
+// FiltersFor returns the filters for the category, where the ones set for
+// the category override the global ones. Filters are pointers so that a
+// category can also override them with zero or false, e.g. min_stars: 0 to
+// disable a global minimum.
+func (c *Config) FiltersFor(category string) Filters {
+	f := c.Filters
+	cf := c.Categories[category].Filters
+	if cf.MaxAge != nil {
+		f.MaxAge = cf.MaxAge
+	}
+	if cf.MinAge != nil {
+		f.MinAge = cf.MinAge
+	}
+	if cf.UpdatedWithin != nil {
+		f.UpdatedWithin = cf.UpdatedWithin
+	}
+	if cf.CreatedWithin != nil {
+		f.CreatedWithin = cf.CreatedWithin
+	}
+	if cf.SkipArchived != nil {
+		f.SkipArchived = cf.SkipArchived
+	}
+	if cf.MinStars != nil {
+		f.MinStars = cf.MinStars
+	}
+	if cf.ActiveWithin != nil {
+		f.ActiveWithin = cf.ActiveWithin
+	}
+	if cf.Languages != nil {
+		f.Languages = cf.Languages
+	}
+	if cf.MinReactions != nil {
+		f.MinReactions = cf.MinReactions
+	}
+	if cf.ExcludeClaimed != nil {
+		f.ExcludeClaimed = cf.ExcludeClaimed
+	}
+	return f
+}
+
+// Qualifiers returns the search qualifiers for the filters, relative to now.
+func (f Filters) Qualifiers(now time.Time) string {
+	var qs []string
+
+	var createdFrom, createdTo time.Time
+	for _, d := range []*Duration{f.MaxAge, f.CreatedWithin} {
+		if d == nil || *d == 0 {
+			continue
+		}
+		if from := now.Add(-time.Duration(*d)); from.After(createdFrom) {
+			createdFrom = from
+		}
+	}
+	if f.MinAge != nil && *f.MinAge != 0 {
+		createdTo = now.Add(-time.Duration(*f.MinAge))
+	}
+	if q := dateQualifier("created", createdFrom, createdTo); q != "" {
+		qs = append(qs, q)
+	}
+
+	if f.UpdatedWithin != nil && *f.UpdatedWithin != 0 {
+		qs = append(qs, dateQualifier("updated", now.Add(-time.Duration(*f.UpdatedWithin)), time.Time{}))
+	}
+	if f.MinReactions != nil && *f.MinReactions > 0 {
+		qs = append(qs, fmt.Sprintf("reactions:>=%d", *f.MinReactions))
+	}
+	return strings.Join(qs, " ")
+}
+
+func dateQualifier(name string, from, to time.Time) string {
+	const layout = "2006-01-02"
+	switch {
+	case !from.IsZero() && !to.IsZero():
+		return fmt.Sprintf("%s:%s..%s", name, from.Format(layout), to.Format(layout))
+	case !from.IsZero():
+		return fmt.Sprintf("%s:>=%s", name, from.Format(layout))
+	case !to.IsZero():
+		return fmt.Sprintf("%s:<=%s", name, to.Format(layout))
+	}
+	return ""
+}
+
+// IsStale reports whether an issue last updated at updatedAt is stale.
+func (c *Config) IsStale(updatedAt, now time.Time) bool {
+	return c.StaleAfter > 0 && now.Sub(updatedAt) > time.Duration(c.StaleAfter)
+}
+
+const (
+	ExcerptNone    = "none"
+	ExcerptColumn  = "column"
+	ExcerptDetails = "details"
+)
+
+// Excerpt configures the plain text excerpt of issue descriptions.
+type Excerpt struct {
+	Mode   string `yaml:"mode" default:"none" schema:"enum=none|column|details" description:"Where the excerpt is shown: nowhere, in an Excerpt column, or in a collapsed details block of that column."`
+	Length int    `yaml:"length" default:"200" schema:"minimum=20" description:"Maximum length of the excerpt in characters."`
+}
+
+// LLM configures the export of issues for language models.
+type LLM struct {
+	Enabled        bool   `yaml:"enabled" default:"false" description:"Write one JSON Lines file per category, one record per issue, and a prompt template."`
+	Directory      string `yaml:"directory" default:"llm" description:"Directory of the export, relative to destination. It is replaced on every run."`
+	Comments       int    `yaml:"comments" default:"5" schema:"minimum=0,maximum=100" description:"Number of most recent comments included in each record."`
+	MaxTokens      int    `yaml:"max_tokens" default:"0" schema:"minimum=0" description:"Split the files of a category into chunks of about this many tokens. Disabled when 0."`
+	PromptTemplate string `yaml:"prompt_template" description:"Go text/template file, relative to the working directory, rendered as prompt.md instead of the default prompt. It gets .Files, .Categories and .Fields."`
+}
+
+// SQLite configures the export of every run to a SQLite database.
+type SQLite struct {
+	Enabled bool   `yaml:"enabled" default:"false" description:"Upsert the issues, labels and repositories found by every run, and a record of the run, into a SQLite database."`
+	Path    string `yaml:"path" default:"history.db" description:"Path of the database, relative to destination. It is kept across runs."`
+}
+
+// Stats configures STATS.md, the trends of the run history.
+type Stats struct {
+	Enabled         bool `yaml:"enabled" default:"false" description:"Write STATS.md with the trends of the run history as Mermaid charts, linked from the index. Requires sqlite.enabled."`
+	Weeks           int  `yaml:"weeks" default:"12" schema:"minimum=1,maximum=104" description:"Number of weeks shown in the charts."`
+	TopRepositories int  `yaml:"top_repositories" default:"10" schema:"minimum=1" description:"Number of fastest-moving repositories listed."`
+}
+
+// CommentActivity configures the summary of the most recent comments of
+// issues.
+type CommentActivity struct {
+	Enabled     bool `yaml:"enabled" default:"false" description:"Add Last Commenter, Maintainer Reply and Claimed columns."`
+	MaxComments int  `yaml:"max_comments" default:"10" schema:"minimum=1,maximum=100" description:"Number of most recent comments fetched per issue. Older comments are ignored, which bounds the API calls to two per issue."`
+}
+
+// Claims configures the detection of issues claimed in comments.
+type Claims struct {
+	Patterns               []string `yaml:"patterns" description:"Regular expressions, matched case-insensitively, of comments asking to work on an issue. Replace the default English, Spanish, French, German, Portuguese, Chinese and Japanese phrases."`
+	Acknowledgements       []string `yaml:"acknowledgements" description:"Regular expressions, matched case-insensitively, of maintainer replies accepting a claim. Replace the default phrases such as 'go ahead' or 'assigned'."`
+	Within                 Duration `yaml:"within" description:"Ignore claims older than this, e.g. 30d. Claims never expire when empty."`
+	RequireAcknowledgement bool     `yaml:"require_acknowledgement" default:"false" description:"Only count claims a maintainer accepted in a later comment."`
+}
+
+// NeedsComments reports whether the comment activity of the issues in the
+// category is needed.
+func (c *Config) NeedsComments(category string) bool {
+	f := c.FiltersFor(category)
+	return c.CommentActivity.Enabled || f.ExcludeClaimed != nil && *f.ExcludeClaimed
+}
+
+// CommentsToFetch returns the number of most recent comments to fetch for
+// each issue of the category.
+func (c *Config) CommentsToFetch(category string) int {
+	n := 0
+	if c.LLM.Enabled {
+		n = c.LLM.Comments
+	}
+	if c.NeedsComments(category) {
+		n = max(n, c.CommentActivity.MaxComments)
+	}
+	return n
+}
+
+// Scoring configures how issues are ranked. Every signal is normalized to
+// the range 0-1 and the score is the weighted average of them, scaled to 100.
+type Scoring struct {
+	Enabled         bool               `yaml:"enabled" default:"false" description:"Add a score column, rank the tables by score and show top picks in the index."`
+	TopPicks        int                `yaml:"top_picks" default:"10" schema:"minimum=0" description:"Number of issues shown in the top picks section of the index."`
+	Labels          map[string]float64 `yaml:"labels" default:"{\"good first issue\":3,\"help wanted\":1}" description:"Weight of each label, merged with the defaults. The labels signal is the highest weight among the labels of an issue relative to the highest weight configured."`
+	RecencyHalfLife int                `yaml:"recency_half_life" default:"30" schema:"minimum=1" description:"Number of days after which the recency signal of an issue is halved."`
+	Weights         ScoringWeights     `yaml:"weights" description:"Weight of each signal in the score."`
+}
+
+type ScoringWeights struct {
+	Labels    float64 `yaml:"labels" default:"3" schema:"minimum=0" description:"Weight of the label weights configured in scoring.labels."`
+	Recency   float64 `yaml:"recency" default:"2" schema:"minimum=0" description:"Weight of how recently the issue was updated."`
+	Comments  float64 `yaml:"comments" default:"1" schema:"minimum=0" description:"Weight of having few comments, i.e. little ongoing discussion."`
+	Body      float64 `yaml:"body" default:"1" schema:"minimum=0" description:"Weight of the length of the issue description."`
+	Structure float64 `yaml:"structure" default:"1" schema:"minimum=0" description:"Weight of reproduction steps, checklists and code blocks in the description."`
+	Activity  float64 `yaml:"activity" default:"1" schema:"minimum=0" description:"Weight of the share of recently updated issues in the same repository."`
+}
+
+// LabelsFor returns the labels to search in the repository, owner/name: the
+// configured labels followed by their aliases in the repository.
+func (c *Config) LabelsFor(ownerRepo string) []string {
+	var aliases map[string][]string
+	for repo, a := range c.LabelAliases {
+		if strings.EqualFold(repo, ownerRepo) {
+			aliases = a
+			break
+		}
+	}
+	if len(aliases) == 0 {
+		return c.Labels
+	}
+
+	labels := slices.Clone(c.Labels)
+	for _, label := range c.Labels {
+		for _, alias := range aliases[label] {
+			if !slices.ContainsFunc(labels, func(l string) bool { return strings.EqualFold(l, alias) }) {
+				labels = append(labels, alias)
+			}
+		}
+	}
+	return labels
+}
+
+// LabelsForQuery returns the labels of the repository, owner/name, as the
+// value of a label search qualifier.
+func (c *Config) LabelsForQuery(ownerRepo string) string {
+	labels := c.LabelsFor(ownerRepo)
+	quoted := make([]string, len(labels))
+	for i, label := range labels {
+		quoted[i] = "\"" + label + "\""
+	}
+	return strings.Join(quoted, ",")
+}
+
+// LoadConfig reads and merges the configuration files, see loadFiles for
+// the merge rules. Unknown keys and values of the wrong type are rejected
+// with ValidationErrors, but the values are not validated; use Validate for
+// that.
+func LoadConfig(filenames ...string) (*Config, error) {
+	config, errs, err := parseConfig(filenames)
 	if err != nil {
 		return nil, err
 	}
+	if len(errs) > 0 {
+		errs.sort()
+		return nil, errs
+	}
+	return config, nil
+}
+
+// ValidateFile loads the configuration files and reports every problem found
+// in them, including unknown keys and invalid values.
+func ValidateFile(filenames ...string) error {
+	config, errs, err := parseConfig(filenames)
+	if err != nil {
+		return err
+	}
+	// Values which could not be decoded are left to their default, so the
+	// problems Validate finds in them are not reported
+	failed := make([]string, len(errs))
+	for i, e := range errs {
+		failed[i] = e.Path
+	}
+	if err := config.Validate(); err != nil {
+		for _, e := range err.(ValidationErrors) {
+			if !slices.ContainsFunc(failed, func(path string) bool { return isUnder(e.Path, path) }) {
+				errs = append(errs, e)
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	errs.sort()
+	return errs
+}
+
+// isUnder reports whether path is parent or a value under it.
+func isUnder(path, parent string) bool {
+	if parent == "" {
+		return false
+	}
+	return path == parent || strings.HasPrefix(path, parent+".") || strings.HasPrefix(path, parent+"[")
+}
+
+// parseConfig loads the configuration files and decodes them as far as
+// possible: values of the wrong type are left to their default. It returns
+// the problems found while loading, with unknown keys and type errors.
+func parseConfig(filenames []string) (*Config, ValidationErrors, error) {
+	if len(filenames) == 0 {
+		return nil, nil, errors.New("no config file specified")
+	}
+
 	var config Config
-	if err := yaml.Unmarshal(data, &config); err != nil {
-		return nil, err
+	root, sources, errs, err := loadFiles(filenames)
+	if err != nil {
+		return nil, nil, err
 	}
+
+	// Defaults are set before decoding so that explicit zero values, e.g. a
+	// weight of 0, are kept. Maps are merged with their defaults.
 	if err := defaults.Set(&config); err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+
+	if root != nil {
+		config.node = root
+		config.sources = sources
+		errs = append(errs, config.checkKnownFields(root, reflect.TypeOf(config), "")...)
+		// Type errors were reported for each file by loadFiles
+		decodeLenient(root, reflect.ValueOf(&config).Elem(), "", sources)
 	}
-	return &config, nil
+	return &config, errs, nil
 }
 
 func ParseRepoURL(url string) (owner, repo string, err error) {
@@ -4,6 +4,7 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -35,11 +36,22 @@ func TestLabelsForQuery(t *testing.T) {
 			},
 			want: "",
 		},
+		{
+			name: "label aliases of the repository",
+			config: &Config{
+				Labels: []string{"good first issue", "help wanted"},
+				LabelAliases: map[string]map[string][]string{
+					"Owner/Repo":  {"good first issue": {"E-easy", "Good First Issue"}},
+					"owner/other": {"help wanted": {"contributions welcome"}},
+				},
+			},
+			want: "\"good first issue\",\"help wanted\",\"E-easy\"",
+		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := tt.config.LabelsForQuery()
+			got := tt.config.LabelsForQuery("owner/repo")
 			assert.Equal(t, tt.want, got)
 		})
 	}
@@ -113,6 +125,35 @@ description: "Custom description"`,
 				assert.Equal(t, "Custom description", c.Description)
 			},
 		},
+		{
+			name: "unknown field",
+			content: `
+repositories:
+  owner1:
+    - repo1
+label:
+  - help wanted`,
+			wantErr:  true,
+			validate: nil,
+		},
+		{
+			name: "filters",
+			content: `
+repositories:
+  a:
+    - https://github.com/owner/repo
+max_age: 1y
+stale_after: 180d
+categories:
+  a:
+    updated_within: 6mo`,
+			wantErr: false,
+			validate: func(t *testing.T, c *Config) {
+				assert.Equal(t, Duration(Year), *c.MaxAge)
+				assert.Equal(t, Duration(180*Day), c.StaleAfter)
+				assert.Equal(t, Duration(6*Month), *c.Categories["a"].UpdatedWithin)
+			},
+		},
 		{
 			name:     "invalid yaml",
 			content:  "invalid: [yaml: content",
@@ -183,3 +224,278 @@ func TestParseRepoURL(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadConfigComposition(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		load     []string
+		env      map[string]string
+		wantErr  string
+		validate func(*testing.T, *Config)
+	}{
+		{
+			name: "multiple files are deep-merged",
+			files: map[string]string{
+				"base.yml": `
+repositories:
+  a:
+    - https://github.com/owner/repo1
+labels:
+  - good first issue
+per_page: 50`,
+				"team.yml": `
+repositories:
+  a:
+    - https://github.com/owner/repo1
+    - https://github.com/owner/repo2
+  b:
+    - https://github.com/owner/repo3
+labels:
+  - help wanted
+per_page: 20`,
+			},
+			load: []string{"base.yml", "team.yml"},
+			validate: func(t *testing.T, c *Config) {
+				assert.Equal(t, map[string][]string{
+					"a": {"https://github.com/owner/repo1", "https://github.com/owner/repo2"},
+					"b": {"https://github.com/owner/repo3"},
+				}, c.Repos)
+				assert.Equal(t, []string{"good first issue", "help wanted"}, c.Labels)
+				assert.Equal(t, 20, c.PerPage)
+			},
+		},
+		{
+			name: "includes with glob are merged before the including file",
+			files: map[string]string{
+				"config.yml": `
+include:
+  - teams/*.yml
+destination: ./out`,
+				"teams/a.yml": `
+repositories:
+  a:
+    - https://github.com/owner/repo1
+destination: ./a`,
+				"teams/b.yml": `
+repositories:
+  b:
+    - https://github.com/owner/repo2
+include: ../common.yml`,
+				"common.yml": `
+labels:
+  - help wanted`,
+			},
+			load: []string{"config.yml"},
+			validate: func(t *testing.T, c *Config) {
+				assert.Equal(t, map[string][]string{
+					"a": {"https://github.com/owner/repo1"},
+					"b": {"https://github.com/owner/repo2"},
+				}, c.Repos)
+				assert.Equal(t, []string{"help wanted"}, c.Labels)
+				assert.Equal(t, "./out", c.Destination)
+				assert.Empty(t, c.Include)
+			},
+		},
+		{
+			name: "environment variables are expanded",
+			files: map[string]string{
+				"config.yml": `
+repositories:
+  a:
+    - https://github.com/${OWNER}/repo
+per_page: ${PER_PAGE}
+destination: ${DEST:-./default}
+description: "costs $${PRICE}"`,
+			},
+			load: []string{"config.yml"},
+			env:  map[string]string{"OWNER": "someone", "PER_PAGE": "30"},
+			validate: func(t *testing.T, c *Config) {
+				assert.Equal(t, []string{"https://github.com/someone/repo"}, c.Repos["a"])
+				assert.Equal(t, 30, c.PerPage)
+				assert.Equal(t, "./default", c.Destination)
+				assert.Equal(t, "costs ${PRICE}", c.Description)
+			},
+		},
+		{
+			name: "undefined environment variable",
+			files: map[string]string{
+				"config.yml": `destination: ${UNDEFINED_ISSUE_SCOUTER_VAR}`,
+			},
+			load:    []string{"config.yml"},
+			wantErr: "environment variable UNDEFINED_ISSUE_SCOUTER_VAR is not set",
+		},
+		{
+			name: "include cycle",
+			files: map[string]string{
+				"a.yml": `include: [b.yml]`,
+				"b.yml": `include: [a.yml]`,
+			},
+			load:    []string{"a.yml"},
+			wantErr: "include cycle detected",
+		},
+		{
+			name: "missing include",
+			files: map[string]string{
+				"a.yml": `include: [missing.yml]`,
+			},
+			load:    []string{"a.yml"},
+			wantErr: "included file not found",
+		},
+		{
+			name: "conflicting types",
+			files: map[string]string{
+				"a.yml": "labels:\n  - bug",
+				"b.yml": "labels: bug",
+			},
+			load:    []string{"a.yml", "b.yml"},
+			wantErr: "cannot merge scalar into sequence",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpDir := t.TempDir()
+			for name, content := range tt.files {
+				path := filepath.Join(tmpDir, name)
+				assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+				assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			files := make([]string, len(tt.load))
+			for i, f := range tt.load {
+				files[i] = filepath.Join(tmpDir, f)
+			}
+
+			config, err := LoadConfig(files...)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			tt.validate(t, config)
+		})
+	}
+}
+
+func TestFiltersFor(t *testing.T) {
+	c := &Config{
+		Filters: Filters{
+			MaxAge:        ptr(Duration(Year)),
+			UpdatedWithin: ptr(Duration(6 * Month)),
+			SkipArchived:  ptr(true),
+			MinStars:      ptr(100),
+			Languages:     []string{"Go"},
+		},
+		Categories: map[string]Category{
+			"a": {Filters: Filters{MaxAge: ptr(Duration(Month)), MinAge: ptr(Duration(Week))}},
+			// Zero values and empty lists override the global filters too
+			"b": {Filters: Filters{UpdatedWithin: ptr(Duration(0)), SkipArchived: ptr(false), MinStars: ptr(0), Languages: []string{}}},
+		},
+	}
+
+	assert.Equal(t, Filters{
+		MaxAge:        ptr(Duration(Month)),
+		MinAge:        ptr(Duration(Week)),
+		UpdatedWithin: ptr(Duration(6 * Month)),
+		SkipArchived:  ptr(true),
+		MinStars:      ptr(100),
+		Languages:     []string{"Go"},
+	}, c.FiltersFor("a"))
+
+	b := c.FiltersFor("b")
+	assert.Equal(t, Filters{
+		MaxAge:        ptr(Duration(Year)),
+		UpdatedWithin: ptr(Duration(0)),
+		SkipArchived:  ptr(false),
+		MinStars:      ptr(0),
+		Languages:     []string{},
+	}, b)
+	assert.False(t, b.FiltersRepositories())
+	assert.Equal(t, "created:>=2024-03-09", b.Qualifiers(time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)))
+
+	assert.Equal(t, c.Filters, c.FiltersFor("c"))
+}
+
+func TestLoadConfigZeroCategoryFilters(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	assert.NoError(t, os.WriteFile(path, []byte(`
+repositories:
+  a:
+    - https://github.com/owner/repo
+min_stars: 100
+skip_archived: true
+categories:
+  a:
+    min_stars: 0
+    skip_archived: false
+`), 0600))
+
+	c, err := LoadConfig(path)
+	assert.NoError(t, err)
+	f := c.FiltersFor("a")
+	assert.Equal(t, 0, *f.MinStars)
+	assert.False(t, *f.SkipArchived)
+	assert.False(t, c.NeedsRepositories("a"))
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestQualifiers(t *testing.T) {
+	now := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		filters Filters
+		want    string
+	}{
+		{
+			name:    "no filters",
+			filters: Filters{},
+			want:    "",
+		},
+		{
+			name:    "max age",
+			filters: Filters{MaxAge: ptr(Duration(Year))},
+			want:    "created:>=2024-03-09",
+		},
+		{
+			name:    "min age",
+			filters: Filters{MinAge: ptr(Duration(Week))},
+			want:    "created:<=2025-03-02",
+		},
+		{
+			name:    "shorter of max age and created within wins",
+			filters: Filters{MaxAge: ptr(Duration(Year)), CreatedWithin: ptr(Duration(Month)), MinAge: ptr(Duration(Week))},
+			want:    "created:2025-02-07..2025-03-02",
+		},
+		{
+			name:    "updated within",
+			filters: Filters{UpdatedWithin: ptr(Duration(90 * Day)), CreatedWithin: ptr(Duration(Year))},
+			want:    "created:>=2024-03-09 updated:>=2024-12-09",
+		},
+		{
+			name:    "min reactions",
+			filters: Filters{UpdatedWithin: ptr(Duration(90 * Day)), MinReactions: ptr(5)},
+			want:    "updated:>=2024-12-09 reactions:>=5",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filters.Qualifiers(now))
+		})
+	}
+}
+
+func TestIsStale(t *testing.T) {
+	now := time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+	c := &Config{StaleAfter: Duration(180 * Day)}
+
+	assert.False(t, c.IsStale(now.Add(-179*24*time.Hour), now))
+	assert.True(t, c.IsStale(now.Add(-181*24*time.Hour), now))
+	assert.False(t, (&Config{}).IsStale(now.AddDate(-5, 0, 0), now))
+}
@@ -0,0 +1,81 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	Day   = 24 * time.Hour
+	Week  = 7 * Day
+	Month = 30 * Day
+	Year  = 365 * Day
+)
+
+// DurationPattern is the format accepted by ParseDuration, published in the
+// JSON Schema. A bare 0 is accepted to turn a filter off.
+const DurationPattern = `^(0|[0-9]+(y|mo|w|d)|([0-9]+(\.[0-9]+)?(ns|us|µs|ms|s|m|h))+)$`
+
+var durationUnitPattern = regexp.MustCompile(`^([0-9]+)(y|mo|w|d)$`)
+
+// Duration is a time.Duration which is written like "90d" in the
+// configuration file. Besides the units of time.ParseDuration, it accepts
+// days (d), weeks (w), months of 30 days (mo) and years of 365 days (y).
+type Duration time.Duration
+
+func ParseDuration(s string) (Duration, error) {
+	if m := durationUnitPattern.FindStringSubmatch(s); m != nil {
+		n, err := strconv.Atoi(m[1])
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q: %w", s, err)
+		}
+		unit := map[string]time.Duration{"y": Year, "mo": Month, "w": Week, "d": Day}[m[2]]
+		return Duration(time.Duration(n) * unit), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid duration %q, use e.g. 90d, 2w, 6mo or 1y", s)
+	}
+	return Duration(d), nil
+}
+
+func (d Duration) String() string {
+	td := time.Duration(d)
+	switch {
+	case td == 0:
+		return "0s"
+	case td%Year == 0:
+		return fmt.Sprintf("%dy", td/Year)
+	case td%Month == 0:
+		return fmt.Sprintf("%dmo", td/Month)
+	case td%Week == 0:
+		return fmt.Sprintf("%dw", td/Week)
+	case td%Day == 0:
+		return fmt.Sprintf("%dd", td/Day)
+	}
+	return td.String()
+}
+
+func (d *Duration) UnmarshalYAML(n *yaml.Node) error {
+	parsed, err := ParseDuration(n.Value)
+	if err != nil {
+		// A TypeError lets the decoder continue and report it with the
+		// other errors.
+		return &yaml.TypeError{Errors: []string{fmt.Sprintf("line %d: %v", n.Line, err)}}
+	}
+	*d = parsed
+	return nil
+}
+
+func (d Duration) MarshalYAML() (any, error) {
+	return d.String(), nil
+}
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.String())
+}
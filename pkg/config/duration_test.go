@@ -0,0 +1,58 @@
+package config
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    Duration
+		wantStr string
+		wantErr bool
+	}{
+		{in: "90d", want: Duration(90 * Day), wantStr: "3mo"},
+		{in: "2w", want: Duration(2 * Week), wantStr: "2w"},
+		{in: "6mo", want: Duration(6 * Month), wantStr: "6mo"},
+		{in: "1y", want: Duration(Year), wantStr: "1y"},
+		{in: "36h", want: Duration(36 * time.Hour), wantStr: "36h0m0s"},
+		{in: "3d", want: Duration(3 * Day), wantStr: "3d"},
+		{in: "0", want: 0, wantStr: "0s"},
+		{in: "0d", want: 0, wantStr: "0s"},
+		{in: "-1h", wantErr: true},
+		{in: "1 year", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			// The schema accepts the same values as the parser
+			assert.Equal(t, !tt.wantErr, regexp.MustCompile(DurationPattern).MatchString(tt.in))
+
+			got, err := ParseDuration(tt.in)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantStr, got.String())
+		})
+	}
+}
+
+func TestDurationUnmarshalYAML(t *testing.T) {
+	var v struct {
+		A Duration `yaml:"a"`
+		B Duration `yaml:"b"`
+	}
+	err := yaml.Unmarshal([]byte("a: 1y\nb: soon\n"), &v)
+
+	assert.EqualError(t, err, "yaml: unmarshal errors:\n  line 2: invalid duration \"soon\", use e.g. 90d, 2w, 6mo or 1y")
+	assert.Equal(t, Duration(Year), v.A)
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+type yamlField struct {
+	name  string
+	field reflect.StructField
+	index []int
+}
+
+// yamlFields returns the fields of the struct t as the yaml package sees
+// them, with the fields of inline structs flattened.
+func yamlFields(t reflect.Type) []yamlField {
+	var fields []yamlField
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+		name, opts, _ := strings.Cut(f.Tag.Get("yaml"), ",")
+		if name == "-" {
+			continue
+		}
+		if opts == "inline" && f.Type.Kind() == reflect.Struct {
+			for _, inner := range yamlFields(f.Type) {
+				inner.index = append([]int{i}, inner.index...)
+				fields = append(fields, inner)
+			}
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(f.Name)
+		}
+		fields = append(fields, yamlField{name: name, field: f, index: []int{i}})
+	}
+	return fields
+}
@@ -0,0 +1,387 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+var envPattern = regexp.MustCompile(`\$\$\{|\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+type loader struct {
+	sources map[*yaml.Node]string
+	loaded  map[string]bool
+	errs    ValidationErrors
+}
+
+// loadFiles reads the given files and the files they include, and deep-merges
+// them into a single YAML document:
+//
+//   - files are merged in the given order, and the files listed in include
+//     are merged before the file including them,
+//   - mappings are merged key by key,
+//   - sequences are concatenated, skipping scalars already present,
+//   - any other value is overridden by the file merged later.
+//
+// ${VAR} and ${VAR:-default} in string values are expanded from the
+// environment before merging, and $${ is kept as a literal ${.
+//
+// Unset variables and values of the wrong type are returned as
+// ValidationErrors along with the document, so that they are reported with
+// the other problems of the files. The error is only set when the files
+// cannot be read or merged.
+func loadFiles(filenames []string) (*yaml.Node, map[*yaml.Node]string, ValidationErrors, error) {
+	l := &loader{
+		sources: make(map[*yaml.Node]string),
+		loaded:  make(map[string]bool),
+	}
+
+	var root *yaml.Node
+	for _, f := range filenames {
+		n, err := l.load(f, nil)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		if root, err = l.merge(root, n); err != nil {
+			return nil, nil, nil, err
+		}
+	}
+	return root, l.sources, l.errs, nil
+}
+
+func (l *loader) load(filename string, stack []string) (*yaml.Node, error) {
+	filename = filepath.Clean(filename)
+	abs, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(stack, abs) {
+		return nil, fmt.Errorf("%s: include cycle detected: %s", filename, strings.Join(append(stack, abs), " -> "))
+	}
+	if l.loaded[abs] {
+		return nil, nil
+	}
+	l.loaded[abs] = true
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("%s:%d:%d: top level must be a mapping", filename, root.Line, root.Column)
+	}
+
+	l.errs = append(l.errs, expandEnv(root, filename, "")...)
+	l.record(root, filename)
+
+	includes, err := takeIncludes(root, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// Decode each file on its own first, so that type errors are reported
+	// even when a value is overridden by another file.
+	l.errs = append(l.errs, decodeLenient(root, reflect.ValueOf(&Config{}).Elem(), "", l.sources)...)
+
+	var merged *yaml.Node
+	for _, pattern := range includes {
+		if !filepath.IsAbs(pattern) {
+			pattern = filepath.Join(filepath.Dir(filename), pattern)
+		}
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid include pattern %s: %w", filename, pattern, err)
+		}
+		if len(matches) == 0 && !hasMeta(pattern) {
+			return nil, fmt.Errorf("%s: included file not found: %s", filename, pattern)
+		}
+		for _, m := range matches {
+			n, err := l.load(m, append(stack, abs))
+			if err != nil {
+				return nil, err
+			}
+			if merged, err = l.merge(merged, n); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return l.merge(merged, root)
+}
+
+// decodeLenient decodes n into v field by field, so that a value of the wrong
+// type only leaves its own field unchanged instead of stopping the decoding.
+// It returns an error for each such value, located with sources.
+func decodeLenient(n *yaml.Node, v reflect.Value, path string, sources map[*yaml.Node]string) ValidationErrors {
+	if n.Kind == yaml.AliasNode {
+		n = n.Alias
+	}
+	t := v.Type()
+	_, custom := v.Addr().Interface().(yaml.Unmarshaler)
+
+	var errs ValidationErrors
+	switch {
+	case !custom && t.Kind() == reflect.Struct && n.Kind == yaml.MappingNode:
+		fields := make(map[string][]int)
+		for _, f := range yamlFields(t) {
+			fields[f.name] = f.index
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i].Value
+			// Unknown keys are reported by checkKnownFields
+			if index, ok := fields[key]; ok {
+				errs = append(errs, decodeLenient(n.Content[i+1], v.FieldByIndex(index), joinPath(path, key), sources)...)
+			}
+		}
+	case !custom && t.Kind() == reflect.Map && t.Key().Kind() == reflect.String && t.Elem().Kind() == reflect.Struct && n.Kind == yaml.MappingNode:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(t))
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := reflect.ValueOf(n.Content[i].Value).Convert(t.Key())
+			elem := reflect.New(t.Elem()).Elem()
+			if current := v.MapIndex(key); current.IsValid() {
+				elem.Set(current)
+			}
+			errs = append(errs, decodeLenient(n.Content[i+1], elem, joinPath(path, n.Content[i].Value), sources)...)
+			v.SetMapIndex(key, elem)
+		}
+	default:
+		decoded := reflect.New(t)
+		decoded.Elem().Set(v)
+		if err := n.Decode(decoded.Interface()); err != nil {
+			return decodeErrors(err, n, path, sources)
+		}
+		v.Set(decoded.Elem())
+	}
+	return errs
+}
+
+var (
+	yamlErrorLine = regexp.MustCompile(`^line (\d+): (.*)$`)
+	yamlErrorTag  = regexp.MustCompile(`^cannot unmarshal (!!\w+)`)
+)
+
+// decodeErrors converts an error decoding n into ValidationErrors located at
+// the values they are about.
+func decodeErrors(err error, n *yaml.Node, path string, sources map[*yaml.Node]string) ValidationErrors {
+	newError := func(at *yaml.Node, msg string) *ValidationError {
+		return &ValidationError{File: sources[at], Path: path, Line: at.Line, Column: at.Column, Message: msg}
+	}
+
+	var typeErr *yaml.TypeError
+	if !errors.As(err, &typeErr) {
+		return ValidationErrors{newError(n, err.Error())}
+	}
+	errs := make(ValidationErrors, len(typeErr.Errors))
+	for i, msg := range typeErr.Errors {
+		at := n
+		if m := yamlErrorLine.FindStringSubmatch(msg); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			var tag string
+			if t := yamlErrorTag.FindStringSubmatch(m[2]); t != nil {
+				tag = t[1]
+			}
+			if found := valueAtLine(n, line, tag); found != nil {
+				at = found
+			}
+			msg = m[2]
+		}
+		errs[i] = newError(at, msg)
+	}
+	return errs
+}
+
+// valueAtLine returns the innermost value under n, mapping keys excluded, on
+// the given line and with the given tag, or any tag when tag is empty.
+func valueAtLine(n *yaml.Node, line int, tag string) *yaml.Node {
+	start := 0
+	step := 1
+	if n.Kind == yaml.MappingNode {
+		start, step = 1, 2
+	}
+	for i := start; i < len(n.Content); i += step {
+		if found := valueAtLine(n.Content[i], line, tag); found != nil {
+			return found
+		}
+	}
+	if n.Line == line && (tag == "" || n.ShortTag() == tag) {
+		return n
+	}
+	return nil
+}
+
+func (l *loader) record(n *yaml.Node, filename string) {
+	l.sources[n] = filename
+	for _, c := range n.Content {
+		l.record(c, filename)
+	}
+}
+
+// merge merges src into dst and returns the result, modifying dst in place.
+func (l *loader) merge(dst, src *yaml.Node) (*yaml.Node, error) {
+	switch {
+	case dst == nil:
+		return src, nil
+	case src == nil:
+		return dst, nil
+	case isNull(src):
+		return dst, nil
+	case isNull(dst):
+		return src, nil
+	}
+	if dst.Kind == yaml.AliasNode {
+		dst = dst.Alias
+	}
+	if src.Kind == yaml.AliasNode {
+		src = src.Alias
+	}
+	if dst.Kind != src.Kind {
+		return nil, fmt.Errorf(
+			"%s:%d:%d: cannot merge %s into %s defined at %s:%d:%d",
+			l.sources[src], src.Line, src.Column, kindName(src), kindName(dst),
+			l.sources[dst], dst.Line, dst.Column,
+		)
+	}
+
+	switch dst.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(src.Content); i += 2 {
+			key, value := src.Content[i], src.Content[i+1]
+			found := false
+			for j := 0; j+1 < len(dst.Content); j += 2 {
+				if dst.Content[j].Value != key.Value {
+					continue
+				}
+				merged, err := l.merge(dst.Content[j+1], value)
+				if err != nil {
+					return nil, err
+				}
+				dst.Content[j+1] = merged
+				found = true
+				break
+			}
+			if !found {
+				dst.Content = append(dst.Content, key, value)
+			}
+		}
+		return dst, nil
+	case yaml.SequenceNode:
+		for _, item := range src.Content {
+			if item.Kind == yaml.ScalarNode && slices.ContainsFunc(dst.Content, func(n *yaml.Node) bool {
+				return n.Kind == yaml.ScalarNode && n.Value == item.Value
+			}) {
+				continue
+			}
+			dst.Content = append(dst.Content, item)
+		}
+		return dst, nil
+	}
+	return src, nil
+}
+
+func takeIncludes(root *yaml.Node, filename string) ([]string, error) {
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		if root.Content[i].Value != "include" {
+			continue
+		}
+		value := root.Content[i+1]
+		root.Content = slices.Delete(root.Content, i, i+2)
+
+		var includes []string
+		switch value.Kind {
+		case yaml.ScalarNode:
+			if !isNull(value) {
+				includes = []string{value.Value}
+			}
+		case yaml.SequenceNode:
+			if err := value.Decode(&includes); err != nil {
+				return nil, fmt.Errorf("%s:%d:%d: include must be a list of file paths", filename, value.Line, value.Column)
+			}
+		default:
+			return nil, fmt.Errorf("%s:%d:%d: include must be a list of file paths", filename, value.Line, value.Column)
+		}
+		return includes, nil
+	}
+	return nil, nil
+}
+
+// expandEnv expands environment variables in the string values under n.
+// Mapping keys are left untouched.
+func expandEnv(n *yaml.Node, filename, path string) ValidationErrors {
+	var errs ValidationErrors
+	switch n.Kind {
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			errs = append(errs, expandEnv(n.Content[i+1], filename, joinPath(path, n.Content[i].Value))...)
+		}
+	case yaml.SequenceNode:
+		for i, c := range n.Content {
+			errs = append(errs, expandEnv(c, filename, fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	case yaml.ScalarNode:
+		if n.Tag != "!!str" || !strings.Contains(n.Value, "${") {
+			return nil
+		}
+		n.Value = envPattern.ReplaceAllStringFunc(n.Value, func(m string) string {
+			if m == "$${" {
+				return "${"
+			}
+			sub := envPattern.FindStringSubmatch(m)
+			if v, ok := os.LookupEnv(sub[1]); ok {
+				return v
+			}
+			if sub[2] != "" {
+				return sub[3]
+			}
+			errs = append(errs, &ValidationError{
+				File:    filename,
+				Path:    path,
+				Line:    n.Line,
+				Column:  n.Column,
+				Message: fmt.Sprintf("environment variable %s is not set", sub[1]),
+			})
+			return ""
+		})
+		// Let plain scalars be resolved again, so that e.g. ${PER_PAGE}
+		// can be decoded into an int.
+		if n.Style == 0 {
+			n.Tag = ""
+		}
+	}
+	return errs
+}
+
+func isNull(n *yaml.Node) bool {
+	return n.Kind == yaml.ScalarNode && n.ShortTag() == "!!null"
+}
+
+func hasMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+func kindName(n *yaml.Node) string {
+	switch n.Kind {
+	case yaml.MappingNode:
+		return "mapping"
+	case yaml.SequenceNode:
+		return "sequence"
+	default:
+		return "scalar"
+	}
+}
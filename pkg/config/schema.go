@@ -0,0 +1,167 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+const (
+	SchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+	SchemaID    = "https://raw.githubusercontent.com/ymtdzzz/issue-scouter/main/schema/config.schema.json"
+)
+
+// Schema is the subset of JSON Schema needed to describe Config.
+type Schema struct {
+	Schema               string             `json:"$schema,omitempty"`
+	ID                   string             `json:"$id,omitempty"`
+	Title                string             `json:"title,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties any                `json:"additionalProperties,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Default              json.RawMessage    `json:"default,omitempty"`
+}
+
+// JSONSchema generates the JSON Schema of the configuration file from the
+// yaml, default, description and schema struct tags of Config.
+//
+// The schema tag accepts comma separated constraints: required,
+// minimum=<n>, maximum=<n> and enum=<a>|<b>.
+func JSONSchema() ([]byte, error) {
+	s, err := schemaOf(reflect.TypeOf(Config{}))
+	if err != nil {
+		return nil, err
+	}
+	s.Schema = SchemaDraft
+	s.ID = SchemaID
+	s.Title = "issue-scouter configuration"
+
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+func schemaOf(t reflect.Type) (*Schema, error) {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if t == reflect.TypeOf(Duration(0)) {
+		return &Schema{Type: "string", Pattern: DurationPattern}, nil
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Schema{Type: "string"}, nil
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}, nil
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}, nil
+	case reflect.Slice:
+		items, err := schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "array", Items: items}, nil
+	case reflect.Map:
+		if t.Key().Kind() != reflect.String {
+			return nil, fmt.Errorf("unsupported map key type: %s", t.Key())
+		}
+		values, err := schemaOf(t.Elem())
+		if err != nil {
+			return nil, err
+		}
+		return &Schema{Type: "object", AdditionalProperties: values}, nil
+	case reflect.Struct:
+		return structSchema(t)
+	}
+	return nil, fmt.Errorf("unsupported type: %s", t)
+}
+
+func structSchema(t reflect.Type) (*Schema, error) {
+	s := &Schema{
+		Type:                 "object",
+		Properties:           make(map[string]*Schema),
+		AdditionalProperties: false,
+	}
+	for _, yf := range yamlFields(t) {
+		name, f := yf.name, yf.field
+
+		fs, err := schemaOf(f.Type)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		fs.Description = f.Tag.Get("description")
+		if d, ok := f.Tag.Lookup("default"); ok {
+			if fs.Default, err = defaultValue(fs.Type, d); err != nil {
+				return nil, fmt.Errorf("%s: invalid default: %w", f.Name, err)
+			}
+		}
+		required, err := applyConstraints(fs, f.Tag.Get("schema"))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		if required {
+			s.Required = append(s.Required, name)
+		}
+		s.Properties[name] = fs
+	}
+	return s, nil
+}
+
+func defaultValue(typ, d string) (json.RawMessage, error) {
+	if typ == "string" {
+		return json.Marshal(d)
+	}
+	if !json.Valid([]byte(d)) {
+		return nil, fmt.Errorf("%q is not valid JSON", d)
+	}
+	return json.RawMessage(d), nil
+}
+
+func applyConstraints(s *Schema, tag string) (required bool, err error) {
+	if tag == "" {
+		return false, nil
+	}
+	for _, c := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(c, "=")
+		switch key {
+		case "required":
+			required = true
+		case "minimum", "maximum":
+			n, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return false, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			if key == "minimum" {
+				s.Minimum = &n
+			} else {
+				s.Maximum = &n
+			}
+		case "enum":
+			// The values of a list are enumerated in its items
+			if s.Items != nil {
+				s.Items.Enum = strings.Split(value, "|")
+			} else {
+				s.Enum = strings.Split(value, "|")
+			}
+		default:
+			return false, fmt.Errorf("unknown schema constraint: %s", key)
+		}
+	}
+	return required, nil
+}
@@ -0,0 +1,57 @@
+package config
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/creasty/defaults"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONSchemaIsUpToDate(t *testing.T) {
+	got, err := JSONSchema()
+	assert.NoError(t, err)
+
+	want, err := os.ReadFile("../../schema/config.schema.json")
+	assert.NoError(t, err)
+	assert.Equal(t, string(want), string(got), "schema/config.schema.json is outdated, run `make schema`")
+}
+
+func TestJSONSchemaDescribesConfig(t *testing.T) {
+	data, err := JSONSchema()
+	assert.NoError(t, err)
+	var s Schema
+	assert.NoError(t, json.Unmarshal(data, &s))
+
+	var c Config
+	assert.NoError(t, defaults.Set(&c))
+	assertSchemaMatches(t, "", &s, reflect.ValueOf(c))
+}
+
+// assertSchemaMatches checks that every field is described and that the
+// default of each property equals the value set by the defaults package.
+func assertSchemaMatches(t *testing.T, path string, s *Schema, v reflect.Value) {
+	t.Helper()
+	fields := yamlFields(v.Type())
+	for _, f := range fields {
+		name := f.name
+		p, ok := s.Properties[name]
+		if !assert.True(t, ok, "%s%s is missing in the schema", path, name) {
+			continue
+		}
+		assert.NotEmpty(t, p.Description, "%s%s has no description", path, name)
+
+		fv := v.FieldByIndex(f.index)
+		if _, ok := f.field.Tag.Lookup("default"); ok {
+			want, err := json.Marshal(fv.Interface())
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(want), string(p.Default), "default of %s%s", path, name)
+		}
+		if f.field.Type.Kind() == reflect.Struct {
+			assertSchemaMatches(t, path+name+".", p, fv)
+		}
+	}
+	assert.Len(t, s.Properties, len(fields), "%s has properties not in the struct", path)
+}
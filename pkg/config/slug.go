@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// slugReplacements spell out symbols which tell names apart, like C and C++.
+var slugReplacements = strings.NewReplacer("+", "-plus-", "#", "-sharp-", "&", "-and-")
+
+// Slug returns a name usable as a single path segment: letters, digits, "_"
+// and "." are kept, runs of other characters become a single "-", and leading
+// or trailing "-" and "." are trimmed, so that "Web / Frontend" becomes
+// "Web-Frontend", "C++" becomes "C-plus-plus" and "../x" becomes "x". The case
+// is kept. It returns an empty string when nothing is left.
+func Slug(name string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range slugReplacements.Replace(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' || r == '.' {
+			if dash && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			dash = false
+			sb.WriteRune(r)
+			continue
+		}
+		dash = true
+	}
+	return strings.Trim(sb.String(), "-.")
+}
+
+// UniqueSlugs returns the slug of each name. Slugs differing only by case
+// would be the same file on some file systems, so names whose slug is taken
+// get a numeric suffix, in the order given. Empty slugs are replaced with
+// fallback.
+func UniqueSlugs(names []string, fallback string) map[string]string {
+	slugs := make(map[string]string, len(names))
+	taken := make(map[string]bool, len(names))
+	for _, name := range names {
+		base := Slug(name)
+		if base == "" {
+			base = fallback
+		}
+		slug := base
+		for i := 2; taken[strings.ToLower(slug)]; i++ {
+			slug = fmt.Sprintf("%s-%d", base, i)
+		}
+		taken[strings.ToLower(slug)] = true
+		slugs[name] = slug
+	}
+	return slugs
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSlug(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "frontend", want: "frontend"},
+		{in: "Web / Frontend", want: "Web-Frontend"},
+		{in: "C++ libs", want: "C-plus-plus-libs"},
+		{in: "C#", want: "C-sharp"},
+		{in: "../x", want: "x"},
+		{in: "a/../../b", want: "a-..-..-b"},
+		{in: ".hidden", want: "hidden"},
+		{in: "日本語 tools", want: "日本語-tools"},
+		{in: "node.js", want: "node.js"},
+		{in: "..", want: ""},
+		{in: " / ", want: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			assert.Equal(t, tt.want, Slug(tt.in))
+		})
+	}
+}
+
+func TestUniqueSlugs(t *testing.T) {
+	got := UniqueSlugs([]string{"C", "C#", "Web Frontend", "web/frontend", "web-frontend", "???"}, "unknown")
+	assert.Equal(t, map[string]string{
+		"C":            "C",
+		"C#":           "C-sharp",
+		"Web Frontend": "Web-Frontend",
+		"web/frontend": "web-frontend-2",
+		"web-frontend": "web-frontend-3",
+		"???":          "unknown",
+	}, got)
+}
@@ -0,0 +1,447 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	MaxPerPage       = 100
+	MinExcerptLength = 20
+	MaxStatsWeeks    = 104
+)
+
+type ValidationError struct {
+	File    string
+	Path    string
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	var sb strings.Builder
+	if e.File != "" {
+		sb.WriteString(e.File + ": ")
+	}
+	if e.Line > 0 {
+		sb.WriteString(fmt.Sprintf("line %d, column %d: ", e.Line, e.Column))
+	}
+	if e.Path != "" {
+		sb.WriteString(e.Path + ": ")
+	}
+	sb.WriteString(e.Message)
+	return sb.String()
+}
+
+type ValidationErrors []*ValidationError
+
+func (es ValidationErrors) Error() string {
+	msgs := make([]string, len(es))
+	for i, e := range es {
+		msgs[i] = e.Error()
+	}
+	return strings.Join(msgs, "\n")
+}
+
+func (es ValidationErrors) sort() {
+	sort.SliceStable(es, func(i, j int) bool {
+		if es[i].File != es[j].File {
+			return es[i].File < es[j].File
+		}
+		if es[i].Line != es[j].Line {
+			return es[i].Line < es[j].Line
+		}
+		return es[i].Column < es[j].Column
+	})
+}
+
+// Validate checks the values of the configuration and returns
+// ValidationErrors describing every problem found, or nil.
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+	add := func(path []any, format string, args ...any) {
+		errs = append(errs, c.newError(path, fmt.Sprintf(format, args...)))
+	}
+
+	if len(c.Repos) == 0 {
+		add([]any{"repositories"}, "at least one category is required")
+	}
+	for category, repos := range c.Repos {
+		if len(repos) == 0 {
+			add([]any{"repositories", category}, "category has no repositories")
+		}
+		seen := make(map[string]int, len(repos))
+		for i, repo := range repos {
+			path := []any{"repositories", category, i}
+			owner, name, err := ParseRepoURL(repo)
+			if err != nil {
+				add(path, "%v", err)
+				continue
+			}
+			if owner == "" || name == "" {
+				add(path, "invalid repository URL: %s", repo)
+				continue
+			}
+			key := strings.ToLower(owner + "/" + name)
+			if first, ok := seen[key]; ok {
+				add(path, "duplicate repository %s/%s (first listed at index %d)", owner, name, first)
+				continue
+			}
+			seen[key] = i
+		}
+	}
+
+	// Category names are used as file names
+	slugs := make(map[string]string, len(c.Repos))
+	for _, category := range slices.Sorted(maps.Keys(c.Repos)) {
+		slug := strings.ToLower(Slug(category))
+		if slug == "" {
+			add([]any{"repositories", category}, "category name must contain a letter or a digit")
+			continue
+		}
+		if other, ok := slugs[slug]; ok {
+			add([]any{"repositories", category}, "category %q has the same file name as %q: %s", category, other, Slug(category))
+			continue
+		}
+		if slug == "languages" && c.HasView(ViewLanguage) {
+			add([]any{"repositories", category}, "category name %q is reserved for the language view", category)
+		}
+		slugs[slug] = category
+	}
+
+	for i, label := range c.Labels {
+		if strings.TrimSpace(label) == "" {
+			add([]any{"labels", i}, "label must not be empty")
+		}
+	}
+
+	for _, repo := range slices.Sorted(maps.Keys(c.LabelAliases)) {
+		if owner, name, ok := strings.Cut(repo, "/"); !ok || owner == "" || name == "" || strings.Contains(name, "/") {
+			add([]any{"label_aliases", repo}, "must be a repository as owner/name, got %s", repo)
+		}
+		for label := range c.LabelAliases[repo] {
+			if !slices.Contains(c.Labels, label) {
+				add([]any{"label_aliases", repo, label}, "label %q is not listed in labels", label)
+			}
+		}
+	}
+
+	if c.PerPage < 1 || c.PerPage > MaxPerPage {
+		add([]any{"per_page"}, "must be between 1 and %d, got %d", MaxPerPage, c.PerPage)
+	}
+
+	if err := CheckDestination(c.Destination); err != nil {
+		add([]any{"destination"}, "%v", err)
+	}
+
+	if c.MaxIssuesPerPage < 0 {
+		add([]any{"max_issues_per_page"}, "must not be negative, got %d", c.MaxIssuesPerPage)
+	}
+
+	switch c.Layout {
+	case "", LayoutFlat, LayoutGrouped:
+	default:
+		add([]any{"layout"}, "unknown layout %q, must be %s or %s", c.Layout, LayoutFlat, LayoutGrouped)
+	}
+
+	for i, v := range c.Views {
+		if v != ViewCategory && v != ViewLanguage {
+			add([]any{"views", i}, "unknown view %q, must be %s or %s", v, ViewCategory, ViewLanguage)
+		}
+	}
+
+	seenTypes := make(map[string]bool)
+	for i, t := range c.ItemTypes {
+		switch {
+		case t != ItemTypeIssue && t != ItemTypePullRequest:
+			add([]any{"item_types", i}, "unknown item type %q, must be %s or %s", t, ItemTypeIssue, ItemTypePullRequest)
+		case seenTypes[t]:
+			add([]any{"item_types", i}, "duplicate item type %q", t)
+		}
+		seenTypes[t] = true
+	}
+
+	if c.LLM.Enabled {
+		errs = append(errs, c.validateLLM()...)
+	}
+
+	if c.SQLite.Enabled {
+		if err := CheckDestination(c.SQLite.Path); err != nil {
+			add([]any{"sqlite", "path"}, "%v", err)
+		} else if path := filepath.Clean(c.SQLite.Path); path == "." || path == "issues" || strings.HasPrefix(path, "issues"+string(filepath.Separator)) {
+			add([]any{"sqlite", "path"}, "must be a file outside of the issue pages, got %s", c.SQLite.Path)
+		}
+	}
+
+	if c.Stats.Enabled {
+		if !c.SQLite.Enabled {
+			add([]any{"stats", "enabled"}, "requires sqlite.enabled")
+		}
+		if c.Stats.Weeks < 1 || c.Stats.Weeks > MaxStatsWeeks {
+			add([]any{"stats", "weeks"}, "must be between 1 and %d, got %d", MaxStatsWeeks, c.Stats.Weeks)
+		}
+		if c.Stats.TopRepositories < 1 {
+			add([]any{"stats", "top_repositories"}, "must be at least 1, got %d", c.Stats.TopRepositories)
+		}
+	}
+
+	needsComments := slices.ContainsFunc(slices.Collect(maps.Keys(c.Repos)), c.NeedsComments)
+	if needsComments && (c.CommentActivity.MaxComments < 1 || c.CommentActivity.MaxComments > MaxPerPage) {
+		add([]any{"comment_activity", "max_comments"}, "must be between 1 and %d, got %d", MaxPerPage, c.CommentActivity.MaxComments)
+	}
+
+	for _, field := range []struct {
+		name     string
+		patterns []string
+	}{{"patterns", c.Claims.Patterns}, {"acknowledgements", c.Claims.Acknowledgements}} {
+		for i, p := range field.patterns {
+			if _, err := regexp.Compile(p); err != nil {
+				add([]any{"claims", field.name, i}, "invalid regular expression: %v", err)
+			}
+		}
+	}
+
+	switch c.Excerpt.Mode {
+	case "", ExcerptNone:
+	case ExcerptColumn, ExcerptDetails:
+		if c.Excerpt.Length < MinExcerptLength {
+			add([]any{"excerpt", "length"}, "must be at least %d, got %d", MinExcerptLength, c.Excerpt.Length)
+		}
+	default:
+		add([]any{"excerpt", "mode"}, "unknown mode %q, must be %s, %s or %s", c.Excerpt.Mode, ExcerptNone, ExcerptColumn, ExcerptDetails)
+	}
+
+	sortFields := []string{SortRepo, SortCreated, SortUpdated, SortComments, SortReactions, SortScore}
+	for i, k := range c.Sort {
+		if !slices.Contains(sortFields, k.Field) {
+			add([]any{"sort", i, "field"}, "unknown field %q, must be one of %s", k.Field, strings.Join(sortFields, ", "))
+		} else if k.Field == SortScore && !c.Scoring.Enabled {
+			add([]any{"sort", i, "field"}, "score requires scoring.enabled")
+		}
+		if k.Order != "" && k.Order != OrderAsc && k.Order != OrderDesc {
+			add([]any{"sort", i, "order"}, "unknown order %q, must be %s or %s", k.Order, OrderAsc, OrderDesc)
+		}
+	}
+
+	switch c.Duplicates {
+	case "", DuplicatesAllow, DuplicatesFirstCategoryWins, DuplicatesCrossReference:
+	default:
+		add([]any{"duplicates"}, "unknown policy %q, must be %s, %s or %s",
+			c.Duplicates, DuplicatesAllow, DuplicatesFirstCategoryWins, DuplicatesCrossReference)
+	}
+
+	for category := range c.Categories {
+		if _, ok := c.Repos[category]; !ok {
+			add([]any{"categories", category}, "category %q is not defined in repositories", category)
+		}
+		if f := c.FiltersFor(category); !f.validAges() {
+			add([]any{"categories", category}, "min_age %s must be shorter than max_age %s", *f.MinAge, *f.MaxAge)
+		}
+	}
+	if !c.Filters.validAges() {
+		add([]any{"min_age"}, "must be shorter than max_age %s, got %s", *c.MaxAge, *c.MinAge)
+	}
+
+	if c.Scoring.Enabled {
+		errs = append(errs, c.validateScoring()...)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+	errs.sort()
+	return errs
+}
+
+func (c *Config) validateLLM() ValidationErrors {
+	var errs ValidationErrors
+	add := func(path []any, format string, args ...any) {
+		errs = append(errs, c.newError(path, fmt.Sprintf(format, args...)))
+	}
+
+	if err := CheckDestination(c.LLM.Directory); err != nil {
+		add([]any{"llm", "directory"}, "%v", err)
+	} else if dir := filepath.Clean(c.LLM.Directory); dir == "." || dir == "issues" || strings.HasPrefix(dir, "issues"+string(filepath.Separator)) {
+		add([]any{"llm", "directory"}, "must not contain the issue pages, got %s", c.LLM.Directory)
+	}
+	if c.LLM.Comments < 0 || c.LLM.Comments > MaxPerPage {
+		add([]any{"llm", "comments"}, "must be between 0 and %d, got %d", MaxPerPage, c.LLM.Comments)
+	}
+	if c.LLM.MaxTokens < 0 {
+		add([]any{"llm", "max_tokens"}, "must not be negative, got %d", c.LLM.MaxTokens)
+	}
+	return errs
+}
+
+func (c *Config) validateScoring() ValidationErrors {
+	var errs ValidationErrors
+	add := func(path []any, format string, args ...any) {
+		errs = append(errs, c.newError(path, fmt.Sprintf(format, args...)))
+	}
+
+	if c.Scoring.TopPicks < 0 {
+		add([]any{"scoring", "top_picks"}, "must not be negative, got %d", c.Scoring.TopPicks)
+	}
+	if c.Scoring.RecencyHalfLife < 1 {
+		add([]any{"scoring", "recency_half_life"}, "must be at least 1 day, got %d", c.Scoring.RecencyHalfLife)
+	}
+	for label, w := range c.Scoring.Labels {
+		if w < 0 {
+			add([]any{"scoring", "labels", label}, "weight must not be negative, got %g", w)
+		}
+	}
+	weights := map[string]float64{
+		"labels":    c.Scoring.Weights.Labels,
+		"recency":   c.Scoring.Weights.Recency,
+		"comments":  c.Scoring.Weights.Comments,
+		"body":      c.Scoring.Weights.Body,
+		"structure": c.Scoring.Weights.Structure,
+		"activity":  c.Scoring.Weights.Activity,
+	}
+	for name, w := range weights {
+		if w < 0 {
+			add([]any{"scoring", "weights", name}, "must not be negative, got %g", w)
+		}
+	}
+	return errs
+}
+
+// CheckDestination reports an error when dest is not a relative path inside
+// the working directory.
+func CheckDestination(dest string) error {
+	if dest == "" {
+		return errors.New("must not be empty")
+	}
+	if filepath.IsAbs(dest) {
+		return fmt.Errorf("must be a relative path, got %s", dest)
+	}
+	cleaned := filepath.Clean(dest)
+	if cleaned == ".." || strings.HasPrefix(cleaned, ".."+string(filepath.Separator)) {
+		return fmt.Errorf("must not point outside of the working directory, got %s", dest)
+	}
+	return nil
+}
+
+func (c *Config) newError(path []any, msg string) *ValidationError {
+	e := &ValidationError{
+		Path:    formatPath(path),
+		Message: msg,
+	}
+	if n := lookupNode(c.node, path); n != nil {
+		e.File, e.Line, e.Column = c.sources[n], n.Line, n.Column
+	}
+	return e
+}
+
+// lookupNode returns the deepest node found along path. Map keys are given as
+// strings and sequence indexes as ints.
+func lookupNode(n *yaml.Node, path []any) *yaml.Node {
+	for _, p := range path {
+		if n == nil {
+			return nil
+		}
+		var next *yaml.Node
+		switch p := p.(type) {
+		case string:
+			if n.Kind != yaml.MappingNode {
+				return n
+			}
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				if n.Content[i].Value == p {
+					next = n.Content[i+1]
+					// Point empty values at their key, since an empty
+					// value has no position of its own.
+					if isNull(next) {
+						next = n.Content[i]
+					}
+					break
+				}
+			}
+		case int:
+			if n.Kind == yaml.SequenceNode && p < len(n.Content) {
+				next = n.Content[p]
+			}
+		}
+		if next == nil {
+			return n
+		}
+		n = next
+	}
+	return n
+}
+
+func formatPath(path []any) string {
+	var sb strings.Builder
+	for _, p := range path {
+		switch p := p.(type) {
+		case string:
+			if sb.Len() > 0 {
+				sb.WriteString(".")
+			}
+			sb.WriteString(p)
+		case int:
+			sb.WriteString("[" + strconv.Itoa(p) + "]")
+		}
+	}
+	return sb.String()
+}
+
+// checkKnownFields reports mapping keys which do not correspond to any field
+// of t, in the same manner as yaml.Decoder.KnownFields but with positions.
+func (c *Config) checkKnownFields(n *yaml.Node, t reflect.Type, path string) ValidationErrors {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	var errs ValidationErrors
+	switch {
+	case t.Kind() == reflect.Struct && n.Kind == yaml.MappingNode:
+		fields := make(map[string]reflect.Type, t.NumField())
+		for _, f := range yamlFields(t) {
+			fields[f.name] = f.field.Type
+		}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			key := n.Content[i]
+			childPath := joinPath(path, key.Value)
+			ft, ok := fields[key.Value]
+			if !ok {
+				errs = append(errs, &ValidationError{
+					File:    c.sources[key],
+					Path:    childPath,
+					Line:    key.Line,
+					Column:  key.Column,
+					Message: fmt.Sprintf("unknown field %q", key.Value),
+				})
+				continue
+			}
+			errs = append(errs, c.checkKnownFields(n.Content[i+1], ft, childPath)...)
+		}
+	case t.Kind() == reflect.Map && n.Kind == yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			errs = append(errs, c.checkKnownFields(n.Content[i+1], t.Elem(), joinPath(path, n.Content[i].Value))...)
+		}
+	case t.Kind() == reflect.Slice && n.Kind == yaml.SequenceNode:
+		for i, item := range n.Content {
+			errs = append(errs, c.checkKnownFields(item, t.Elem(), fmt.Sprintf("%s[%d]", path, i))...)
+		}
+	}
+	return errs
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,321 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidate(t *testing.T) {
+	excludeClaimed := true
+	tests := []struct {
+		name   string
+		config *Config
+		want   []string
+	}{
+		{
+			name: "valid config",
+			config: &Config{
+				Repos:       map[string][]string{"a": {"https://github.com/owner/repo"}},
+				Labels:      []string{"good first issue"},
+				PerPage:     100,
+				Destination: ".",
+			},
+			want: nil,
+		},
+		{
+			name: "no repositories",
+			config: &Config{
+				PerPage:     100,
+				Destination: ".",
+			},
+			want: []string{"repositories: at least one category is required"},
+		},
+		{
+			name: "invalid values",
+			config: &Config{
+				Repos: map[string][]string{
+					"a": {
+						"https://github.com/owner/repo",
+						"https://github.com/Owner/Repo",
+						"https://gitlab.com/owner/repo",
+					},
+					"b": {},
+				},
+				Labels:      []string{" "},
+				PerPage:     101,
+				Destination: "/etc",
+			},
+			want: []string{
+				"repositories.a[1]: duplicate repository Owner/Repo (first listed at index 0)",
+				"repositories.a[2]: not a valid GitHub URL: https://gitlab.com/owner/repo",
+				"repositories.b: category has no repositories",
+				"labels[0]: label must not be empty",
+				"per_page: must be between 1 and 100, got 101",
+				"destination: must be a relative path, got /etc",
+			},
+		},
+		{
+			name: "invalid filters",
+			config: &Config{
+				Repos:       map[string][]string{"a": {"https://github.com/owner/repo"}},
+				PerPage:     100,
+				Destination: ".",
+				Filters:     Filters{MinAge: ptr(Duration(Year)), MaxAge: ptr(Duration(Month))},
+				Categories: map[string]Category{
+					"unknown": {},
+					"a":       {Filters: Filters{MaxAge: ptr(Duration(2 * Year))}},
+				},
+			},
+			want: []string{
+				"min_age: must be shorter than max_age 1mo, got 1y",
+				`categories.unknown: category "unknown" is not defined in repositories`,
+				"categories.unknown: min_age 1y must be shorter than max_age 1mo",
+			},
+		},
+		{
+			name: "destination outside of working directory",
+			config: &Config{
+				Repos:       map[string][]string{"a": {"https://github.com/owner/repo"}},
+				PerPage:     100,
+				Destination: "out/../../x",
+			},
+			want: []string{"destination: must not point outside of the working directory, got out/../../x"},
+		},
+		{
+			name: "invalid label aliases",
+			config: &Config{
+				Repos:       map[string][]string{"a": {"https://github.com/owner/repo"}},
+				Labels:      []string{"good first issue"},
+				PerPage:     100,
+				Destination: ".",
+				LabelAliases: map[string]map[string][]string{
+					"owner/repo": {"good first issue": {"E-easy"}, "help wanted": {"E-help"}},
+					"repo":       {"good first issue": {"beginner"}},
+				},
+			},
+			want: []string{
+				`label_aliases.owner/repo.help wanted: label "help wanted" is not listed in labels`,
+				"label_aliases.repo: must be a repository as owner/name, got repo",
+			},
+		},
+		{
+			name: "invalid llm export",
+			config: &Config{
+				Repos:       map[string][]string{"a": {"https://github.com/owner/repo"}},
+				PerPage:     100,
+				Destination: ".",
+				LLM:         LLM{Enabled: true, Directory: "issues/llm", Comments: 101, MaxTokens: -1},
+			},
+			want: []string{
+				"llm.directory: must not contain the issue pages, got issues/llm",
+				"llm.comments: must be between 0 and 100, got 101",
+				"llm.max_tokens: must not be negative, got -1",
+			},
+		},
+		{
+			name: "too many comments for claimed issues",
+			config: &Config{
+				Repos:       map[string][]string{"a": {"https://github.com/owner/repo"}},
+				PerPage:     100,
+				Destination: ".",
+				Categories: map[string]Category{
+					"a": {Filters: Filters{ExcludeClaimed: &excludeClaimed}},
+				},
+				CommentActivity: CommentActivity{MaxComments: 101},
+			},
+			want: []string{"comment_activity.max_comments: must be between 1 and 100, got 101"},
+		},
+		{
+			name: "invalid claim patterns",
+			config: &Config{
+				Repos:       map[string][]string{"a": {"https://github.com/owner/repo"}},
+				PerPage:     100,
+				Destination: ".",
+				Claims:      Claims{Patterns: []string{`\bdibs\b`, "(unclosed"}},
+			},
+			want: []string{"claims.patterns[1]: invalid regular expression: error parsing regexp: missing closing ): `(unclosed`"},
+		},
+		{
+			name: "category names used as file names",
+			config: &Config{
+				Repos: map[string][]string{
+					"Web / Frontend": {"https://github.com/owner/repo"},
+					"web-frontend":   {"https://github.com/owner/repo2"},
+					"../":            {"https://github.com/owner/repo3"},
+					"Languages":      {"https://github.com/owner/repo4"},
+				},
+				PerPage:     100,
+				Destination: ".",
+				Views:       []string{ViewCategory, ViewLanguage},
+			},
+			want: []string{
+				`repositories.../: category name must contain a letter or a digit`,
+				`repositories.web-frontend: category "web-frontend" has the same file name as "Web / Frontend": web-frontend`,
+				`repositories.Languages: category name "Languages" is reserved for the language view`,
+			},
+		},
+		{
+			name: "sqlite database inside the issue pages",
+			config: &Config{
+				Repos:       map[string][]string{"a": {"https://github.com/owner/repo"}},
+				PerPage:     100,
+				Destination: ".",
+				SQLite:      SQLite{Enabled: true, Path: "issues/history.db"},
+			},
+			want: []string{"sqlite.path: must be a file outside of the issue pages, got issues/history.db"},
+		},
+		{
+			name: "stats without sqlite",
+			config: &Config{
+				Repos:       map[string][]string{"a": {"https://github.com/owner/repo"}},
+				PerPage:     100,
+				Destination: ".",
+				Stats:       Stats{Enabled: true, Weeks: 200, TopRepositories: 0},
+			},
+			want: []string{
+				"stats.enabled: requires sqlite.enabled",
+				"stats.weeks: must be between 1 and 104, got 200",
+				"stats.top_repositories: must be at least 1, got 0",
+			},
+		},
+		{
+			name: "excerpt too short",
+			config: &Config{
+				Repos:       map[string][]string{"a": {"https://github.com/owner/repo"}},
+				PerPage:     100,
+				Destination: ".",
+				Excerpt:     Excerpt{Mode: ExcerptColumn, Length: 5},
+			},
+			want: []string{"excerpt.length: must be at least 20, got 5"},
+		},
+		{
+			name: "unknown enumerated values",
+			config: &Config{
+				Repos:       map[string][]string{"a": {"https://github.com/owner/repo"}},
+				PerPage:     100,
+				Destination: ".",
+				Views:       []string{ViewLanguage, "repository"},
+				Duplicates:  "drop",
+				Layout:      "tabs",
+				Sort:        []SortKey{{Field: "stars"}, {Field: SortScore}, {Field: SortComments, Order: "up"}},
+				ItemTypes:   []string{ItemTypePullRequest, "discussion", ItemTypePullRequest},
+				Excerpt:     Excerpt{Mode: "tooltip"},
+			},
+			want: []string{
+				`excerpt.mode: unknown mode "tooltip", must be none, column or details`,
+				`item_types[1]: unknown item type "discussion", must be issue or pr`,
+				`item_types[2]: duplicate item type "pr"`,
+				`views[1]: unknown view "repository", must be category or language`,
+				`duplicates: unknown policy "drop", must be allow, first-category-wins or cross-reference`,
+				`layout: unknown layout "tabs", must be flat or grouped`,
+				`sort[0].field: unknown field "stars", must be one of repo, created, updated, comments, reactions, score`,
+				`sort[1].field: score requires scoring.enabled`,
+				`sort[2].order: unknown order "up", must be asc or desc`,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			errs, ok := err.(ValidationErrors)
+			assert.True(t, ok)
+			got := make([]string, len(errs))
+			for i, e := range errs {
+				got[i] = e.Error()
+			}
+			assert.ElementsMatch(t, tt.want, got)
+		})
+	}
+}
+
+func TestValidateFile(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []ValidationError
+	}{
+		{
+			name: "valid",
+			content: `
+repositories:
+  a:
+    - https://github.com/owner/repo`,
+			want: nil,
+		},
+		{
+			name: "reports every problem with position",
+			content: `repositories:
+  a:
+    - https://github.com/owner/repo
+    - invalid
+  b:
+label:
+  - bug
+per_page: 101`,
+			want: []ValidationError{
+				{Path: "repositories.a[1]", Line: 4, Column: 7, Message: "not a valid GitHub URL: invalid"},
+				{Path: "repositories.b", Line: 5, Column: 3, Message: "category has no repositories"},
+				{Path: "label", Line: 6, Column: 1, Message: `unknown field "label"`},
+				{Path: "per_page", Line: 8, Column: 11, Message: "must be between 1 and 100, got 101"},
+			},
+		},
+		{
+			name: "type errors do not stop the other checks",
+			content: `repositories:
+  a:
+    - invalid
+per_page: abc
+max_age: banana
+labels: [bug, [nested]]`,
+			want: []ValidationError{
+				{Path: "repositories.a[0]", Line: 3, Column: 7, Message: "not a valid GitHub URL: invalid"},
+				{Path: "per_page", Line: 4, Column: 11, Message: "cannot unmarshal !!str `abc` into int"},
+				{Path: "max_age", Line: 5, Column: 10, Message: `invalid duration "banana", use e.g. 90d, 2w, 6mo or 1y`},
+				{Path: "labels", Line: 6, Column: 15, Message: "cannot unmarshal !!seq into string"},
+			},
+		},
+		{
+			name: "reports type errors, unknown keys and invalid values together",
+			content: `repositories: not-a-mapping
+per_page: 0
+stale_after: soon
+categorys: {}`,
+			want: []ValidationError{
+				{Path: "repositories", Line: 1, Column: 15, Message: "cannot unmarshal !!str `not-a-m...` into map[string][]string"},
+				{Path: "per_page", Line: 2, Column: 11, Message: "must be between 1 and 100, got 0"},
+				{Path: "stale_after", Line: 3, Column: 14, Message: `invalid duration "soon", use e.g. 90d, 2w, 6mo or 1y`},
+				{Path: "categorys", Line: 4, Column: 1, Message: `unknown field "categorys"`},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpFile := filepath.Join(t.TempDir(), "config.yml")
+			assert.NoError(t, os.WriteFile(tmpFile, []byte(tt.content), 0644))
+
+			err := ValidateFile(tmpFile)
+			if tt.want == nil {
+				assert.NoError(t, err)
+				return
+			}
+			errs, ok := err.(ValidationErrors)
+			assert.True(t, ok)
+			got := make([]ValidationError, len(errs))
+			for i, e := range errs {
+				assert.Equal(t, tmpFile, e.File)
+				got[i] = *e
+				got[i].File = ""
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
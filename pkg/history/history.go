@@ -0,0 +1,263 @@
+// Package history stores the issues found by every run in a SQLite database,
+// to analyse how they evolve over time. The tables are described in
+// schema.sql.
+package history
+
+import (
+	"context"
+	"database/sql"
+	_ "embed"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+
+	// Pure Go SQLite driver, registered as "sqlite"
+	_ "modernc.org/sqlite"
+)
+
+//go:embed schema.sql
+var schema string
+
+// DB is a history database.
+type DB struct {
+	db *sql.DB
+}
+
+// Open opens the database at path, creating it and its tables if needed.
+func Open(path string) (*DB, error) {
+	db, err := sql.Open("sqlite", fileURI(path, ""))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to create the tables of %s: %w", path, err)
+	}
+	return &DB{db: db}, nil
+}
+
+// OpenReadOnly opens the existing database at path without creating or
+// changing anything. It returns an error wrapping fs.ErrNotExist when the
+// database does not exist.
+func OpenReadOnly(path string) (*DB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", fileURI(path, "mode=ro"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return &DB{db: db}, nil
+}
+
+// fileURI returns the URI of the database at path with the query. The driver
+// takes everything after a "?" as its parameters, so the path is escaped. It
+// is not built as a url.URL, which would turn the first directory of a
+// relative path into the authority.
+func fileURI(path, query string) string {
+	uri := "file:" + (&url.URL{Path: path}).EscapedPath()
+	if query != "" {
+		uri += "?" + query
+	}
+	return uri
+}
+
+func (h *DB) Close() error {
+	return h.db.Close()
+}
+
+// Record adds a run started at startedAt and upserts the issues it found and
+// the configured repositories, in a single transaction. It returns the ID of
+// the run.
+func (h *DB) Record(c *config.Config, startedAt time.Time, issues client.Issues, repos client.Repositories) (int64, error) {
+	r, err := h.Begin(c, startedAt, issues, repos)
+	if err != nil {
+		return 0, err
+	}
+	if err := r.Commit(); err != nil {
+		return 0, err
+	}
+	return r.ID, nil
+}
+
+// Run is a run recorded in a transaction not committed yet. Its Trends
+// include it, while other connections do not see it until Commit.
+type Run struct {
+	ID int64
+	tx *sql.Tx
+}
+
+// Begin records a run like Record, without committing it. Either Commit or
+// Rollback must be called.
+func (h *DB) Begin(c *config.Config, startedAt time.Time, issues client.Issues, repos client.Repositories) (*Run, error) {
+	ctx := context.Background()
+	tx, err := h.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	run, err := record(ctx, tx, c, startedAt, issues, repos)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	return &Run{ID: run, tx: tx}, nil
+}
+
+// Commit makes the run visible to the other connections.
+func (r *Run) Commit() error {
+	return r.tx.Commit()
+}
+
+// Rollback drops the run. It does nothing once the run is committed.
+func (r *Run) Rollback() error {
+	if err := r.tx.Rollback(); err != nil && !errors.Is(err, sql.ErrTxDone) {
+		return err
+	}
+	return nil
+}
+
+func record(ctx context.Context, tx *sql.Tx, c *config.Config, startedAt time.Time, issues client.Issues, repos client.Repositories) (int64, error) {
+	// Issues are identified by their URL, as when they are deduplicated and
+	// scored
+	unique := make(map[string]*client.Issue)
+	for _, list := range issues {
+		for _, issue := range list {
+			unique[issue.GetURL()] = issue
+		}
+	}
+
+	res, err := tx.ExecContext(ctx, `INSERT INTO runs (started_at, issues) VALUES (?, ?)`, formatTime(startedAt), len(unique))
+	if err != nil {
+		return 0, fmt.Errorf("failed to insert the run: %w", err)
+	}
+	run, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, category := range slices.Sorted(maps.Keys(repos)) {
+		// Without metadata, only the owner and name are known
+		withMetadata := c.NeedsRepositories(category)
+		for _, repo := range repos[category] {
+			if err := upsertRepository(ctx, tx, run, repo.Repository, withMetadata); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	for _, url := range slices.Sorted(maps.Keys(unique)) {
+		if err := upsertIssue(ctx, tx, run, unique[url], c.Scoring.Enabled); err != nil {
+			return 0, err
+		}
+	}
+
+	for _, category := range slices.Sorted(maps.Keys(issues)) {
+		for _, issue := range issues[category] {
+			_, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO run_issues (run_id, category, issue_url) VALUES (?, ?, ?)`,
+				run, category, issue.GetURL())
+			if err != nil {
+				return 0, fmt.Errorf("failed to insert %s in the run: %w", issue.GetURL(), err)
+			}
+		}
+	}
+	return run, nil
+}
+
+func upsertRepository(ctx context.Context, tx *sql.Tx, run int64, repo *client.Repository, withMetadata bool) error {
+	fullName := repoName(repo.Owner, repo.Name)
+	var err error
+	if withMetadata {
+		_, err = tx.ExecContext(ctx, `
+INSERT INTO repositories (full_name, description, language, stars, license, archived, pushed_at, first_seen_run, last_seen_run)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (full_name) DO UPDATE SET
+    description = excluded.description,
+    language = excluded.language,
+    stars = excluded.stars,
+    license = excluded.license,
+    archived = excluded.archived,
+    pushed_at = excluded.pushed_at,
+    last_seen_run = excluded.last_seen_run`,
+			fullName, repo.Description, repo.Language, repo.Stars, repo.License, repo.Archived, formatTime(repo.PushedAt), run, run)
+	} else {
+		_, err = tx.ExecContext(ctx, `
+INSERT INTO repositories (full_name, first_seen_run, last_seen_run) VALUES (?, ?, ?)
+ON CONFLICT (full_name) DO UPDATE SET last_seen_run = excluded.last_seen_run`,
+			fullName, run, run)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to upsert repository %s: %w", fullName, err)
+	}
+	return nil
+}
+
+func upsertIssue(ctx context.Context, tx *sql.Tx, run int64, issue *client.Issue, scored bool) error {
+	url := issue.GetURL()
+	owner, name, err := config.ParseRepoURL(url)
+	if err != nil {
+		return err
+	}
+	fullName := repoName(owner, name)
+	// A score of 0 is a real score when scoring is enabled
+	score := sql.NullFloat64{Float64: issue.Score, Valid: scored}
+
+	// Issues of repositories dropped from the config may still be found
+	// when they are listed in another category
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO repositories (full_name, first_seen_run, last_seen_run) VALUES (?, ?, ?)
+ON CONFLICT (full_name) DO UPDATE SET last_seen_run = excluded.last_seen_run`,
+		fullName, run, run)
+	if err != nil {
+		return fmt.Errorf("failed to upsert repository %s: %w", fullName, err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO issues (url, repository, number, title, pull_request, created_at, updated_at, comments, reactions, score, first_seen_run, last_seen_run)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT (url) DO UPDATE SET
+    title = excluded.title,
+    updated_at = excluded.updated_at,
+    comments = excluded.comments,
+    reactions = excluded.reactions,
+    score = excluded.score,
+    last_seen_run = excluded.last_seen_run`,
+		url, fullName, issue.GetNumber(), issue.GetTitle(), issue.IsPullRequest(),
+		formatTime(issue.GetCreatedAt().Time), formatTime(issue.GetUpdatedAt().Time),
+		issue.GetComments(), issue.GetReactions().GetTotalCount(), score, run, run)
+	if err != nil {
+		return fmt.Errorf("failed to upsert issue %s: %w", url, err)
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM labels WHERE issue_url = ?`, url); err != nil {
+		return fmt.Errorf("failed to update the labels of %s: %w", url, err)
+	}
+	for _, l := range issue.Labels {
+		if _, err := tx.ExecContext(ctx, `INSERT OR IGNORE INTO labels (issue_url, name) VALUES (?, ?)`, url, l.GetName()); err != nil {
+			return fmt.Errorf("failed to update the labels of %s: %w", url, err)
+		}
+	}
+	return nil
+}
+
+// repoName returns the key of a repository, owner/name in lower case as
+// GitHub names are case-insensitive.
+func repoName(owner, name string) string {
+	return strings.ToLower(owner + "/" + name)
+}
+
+// formatTime formats t as RFC 3339 in UTC, or returns nil for the zero time
+// so that it is stored as NULL.
+func formatTime(t time.Time) any {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UTC().Format(time.RFC3339)
+}
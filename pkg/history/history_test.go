@@ -0,0 +1,267 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-github/v69/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func newIssue(url, title string, created time.Time, labels ...string) *client.Issue {
+	issue := &client.Issue{Issue: &github.Issue{
+		URL:       github.Ptr(url),
+		HTMLURL:   github.Ptr(url),
+		Title:     github.Ptr(title),
+		CreatedAt: &github.Timestamp{Time: created},
+		UpdatedAt: &github.Timestamp{Time: created},
+		Comments:  github.Ptr(1),
+	}}
+	for _, l := range labels {
+		issue.Labels = append(issue.Labels, &github.Label{Name: github.Ptr(l)})
+	}
+	return issue
+}
+
+func TestRecord(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	day1 := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	day11 := day1.AddDate(0, 0, 10)
+	co := &config.Config{}
+	repos := client.Repositories{
+		// Repository names are case-insensitive
+		"a": {{Repository: &client.Repository{Owner: "Owner", Name: "Repo"}}},
+	}
+
+	first := newIssue("https://github.com/owner/repo/issues/1", "First", day1.AddDate(0, 0, -5), "good first issue")
+	second := newIssue("https://github.com/owner/repo/issues/2", "Second", day1, "good first issue", "bug")
+	run, err := db.Record(co, day1, client.Issues{"a": {first, second}, "b": {second}}, repos)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), run)
+
+	// The first issue was closed and the second one lost a label
+	second.Title = github.Ptr("Second, renamed")
+	second.Labels = second.Labels[:1]
+	co.Filters.MinStars = github.Ptr(10)
+	repos["a"][0].Stars = 20
+	repos["a"][0].Language = "Go"
+	run, err = db.Record(co, day11, client.Issues{"a": {second}}, repos)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), run)
+
+	rows, err := db.db.Query(`SELECT started_at, issues FROM runs ORDER BY id`)
+	assert.NoError(t, err)
+	var runs []string
+	for rows.Next() {
+		var startedAt string
+		var n int
+		assert.NoError(t, rows.Scan(&startedAt, &n))
+		runs = append(runs, fmt.Sprintf("%s %d", startedAt, n))
+	}
+	assert.NoError(t, rows.Close())
+	assert.Equal(t, []string{"2025-03-01T00:00:00Z 2", "2025-03-11T00:00:00Z 1"}, runs)
+
+	var title string
+	var firstSeen, lastSeen int
+	assert.NoError(t, db.db.QueryRow(`SELECT title, first_seen_run, last_seen_run FROM issues WHERE url = ?`, second.GetURL()).Scan(&title, &firstSeen, &lastSeen))
+	assert.Equal(t, "Second, renamed", title)
+	assert.Equal(t, 1, firstSeen)
+	assert.Equal(t, 2, lastSeen)
+
+	var labels int
+	assert.NoError(t, db.db.QueryRow(`SELECT count(*) FROM labels WHERE issue_url = ?`, second.GetURL()).Scan(&labels))
+	assert.Equal(t, 1, labels)
+
+	var found int
+	assert.NoError(t, db.db.QueryRow(`SELECT count(*) FROM run_issues WHERE run_id = 1`).Scan(&found))
+	assert.Equal(t, 3, found)
+
+	var language string
+	var stars int
+	assert.NoError(t, db.db.QueryRow(`SELECT language, stars FROM repositories WHERE full_name = 'owner/repo'`).Scan(&language, &stars))
+	assert.Equal(t, "Go", language)
+	assert.Equal(t, 20, stars)
+	var repositories int
+	assert.NoError(t, db.db.QueryRow(`SELECT count(*) FROM repositories`).Scan(&repositories))
+	assert.Equal(t, 1, repositories)
+
+	// The query documented in the README
+	var median float64
+	assert.NoError(t, db.db.QueryRow(`
+WITH gone AS (
+    SELECT julianday(r.started_at) - julianday(i.created_at) AS days
+    FROM issues i
+    JOIN runs r ON r.id = i.last_seen_run
+    JOIN labels l ON l.issue_url = i.url AND l.name = 'good first issue'
+    WHERE i.last_seen_run < (SELECT max(id) FROM runs)
+)
+SELECT days FROM gone ORDER BY days LIMIT 1 OFFSET (SELECT count(*) FROM gone) / 2`).Scan(&median))
+	assert.Equal(t, 5.0, median)
+}
+
+func TestOpenExistingDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	for range 2 {
+		db, err := Open(path)
+		assert.NoError(t, err)
+		_, err = db.Record(&config.Config{}, time.Now(), client.Issues{}, client.Repositories{})
+		assert.NoError(t, err)
+		assert.NoError(t, db.Close())
+	}
+
+	db, err := Open(path)
+	assert.NoError(t, err)
+	defer db.Close()
+	var runs int
+	assert.NoError(t, db.db.QueryRow(`SELECT count(*) FROM runs`).Scan(&runs))
+	assert.Equal(t, 2, runs)
+}
+
+func TestFileURI(t *testing.T) {
+	assert.Equal(t, "file:output/history.db", fileURI("output/history.db", ""))
+	assert.Equal(t, "file:/tmp/a%3Fb/a%23b/a%2520b/h.db?mode=ro", fileURI("/tmp/a?b/a#b/a%20b/h.db", "mode=ro"))
+}
+
+func TestOpenEscapesPath(t *testing.T) {
+	for _, dir := range []string{"a?b", "a#b", "a%20b", "a b"} {
+		t.Run(dir, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), dir, "history.db")
+			assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+			db, err := Open(path)
+			assert.NoError(t, err)
+			defer db.Close()
+			_, err = db.Record(&config.Config{}, time.Now(), client.Issues{}, client.Repositories{})
+			assert.NoError(t, err)
+			assert.FileExists(t, path)
+		})
+	}
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.db")
+	_, err := OpenReadOnly(path)
+	assert.ErrorIs(t, err, fs.ErrNotExist)
+	assert.NoFileExists(t, path)
+
+	db, err := Open(path)
+	assert.NoError(t, err)
+	_, err = db.Record(&config.Config{}, time.Now(), client.Issues{}, client.Repositories{})
+	assert.NoError(t, err)
+	assert.NoError(t, db.Close())
+
+	db, err = OpenReadOnly(path)
+	assert.NoError(t, err)
+	defer db.Close()
+	trends, err := db.Trends(time.Now(), 4)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, trends.Runs)
+	_, err = db.Record(&config.Config{}, time.Now(), client.Issues{}, client.Repositories{})
+	assert.Error(t, err, "a read-only database must not be changed")
+}
+
+func TestOpenReadOnlyEscapesPath(t *testing.T) {
+	for _, dir := range []string{"a?b", "a#b", "a%20b", "a b"} {
+		t.Run(dir, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), dir, "history.db")
+			assert.NoError(t, os.MkdirAll(filepath.Dir(path), 0750))
+			db, err := Open(path)
+			assert.NoError(t, err)
+			_, err = db.Record(&config.Config{}, time.Now(), client.Issues{}, client.Repositories{})
+			assert.NoError(t, err)
+			assert.NoError(t, db.Close())
+
+			db, err = OpenReadOnly(path)
+			assert.NoError(t, err)
+			defer db.Close()
+			trends, err := db.Trends(time.Now(), 4)
+			assert.NoError(t, err)
+			assert.Equal(t, 1, trends.Runs)
+		})
+	}
+}
+
+func TestBegin(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	now := time.Now()
+	issues := client.Issues{"a": {newIssue("https://github.com/owner/repo/issues/1", "First", now)}}
+	run, err := db.Begin(&config.Config{}, now, issues, client.Repositories{})
+	assert.NoError(t, err)
+	trends, err := run.Trends(now, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, trends.Runs)
+
+	// A rolled back run is not recorded
+	assert.NoError(t, run.Rollback())
+	trends, err = db.Trends(now, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, trends.Runs)
+
+	run, err = db.Begin(&config.Config{}, now, issues, client.Repositories{})
+	assert.NoError(t, err)
+	assert.NoError(t, run.Commit())
+	assert.NoError(t, run.Rollback(), "rolling back a committed run does nothing")
+	trends, err = db.Trends(now, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, trends.Runs)
+}
+
+func TestRecordScores(t *testing.T) {
+	tests := []struct {
+		name    string
+		enabled bool
+		want    []sql.NullFloat64
+	}{
+		{
+			name:    "stores scores of 0 when scoring is enabled",
+			enabled: true,
+			want:    []sql.NullFloat64{{Float64: 0, Valid: true}, {Float64: 42, Valid: true}},
+		},
+		{
+			name:    "stores NULL when scoring is disabled",
+			enabled: false,
+			want:    []sql.NullFloat64{{}, {}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, err := Open(filepath.Join(t.TempDir(), "history.db"))
+			assert.NoError(t, err)
+			defer db.Close()
+
+			now := time.Now()
+			zero := newIssue("https://github.com/owner/repo/issues/1", "Zero", now)
+			scored := newIssue("https://github.com/owner/repo/issues/2", "Scored", now)
+			if tt.enabled {
+				scored.Score = 42
+			}
+			co := &config.Config{Scoring: config.Scoring{Enabled: tt.enabled}}
+			_, err = db.Record(co, now, client.Issues{"a": {zero, scored}}, client.Repositories{})
+			assert.NoError(t, err)
+
+			rows, err := db.db.Query(`SELECT score FROM issues ORDER BY url`)
+			assert.NoError(t, err)
+			defer rows.Close()
+			var got []sql.NullFloat64
+			for rows.Next() {
+				var score sql.NullFloat64
+				assert.NoError(t, rows.Scan(&score))
+				got = append(got, score)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
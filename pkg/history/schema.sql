@@ -0,0 +1,58 @@
+-- Schema of the SQLite export. Times are RFC 3339 strings in UTC, which
+-- SQLite date and time functions such as julianday() accept.
+
+-- runs has a row per run of issue-scouter.
+CREATE TABLE IF NOT EXISTS runs (
+    id         INTEGER PRIMARY KEY AUTOINCREMENT,
+    started_at TEXT    NOT NULL,
+    issues     INTEGER NOT NULL -- distinct issues and pull requests found
+);
+
+-- repositories has a row per configured repository. The metadata columns
+-- are NULL until the metadata is fetched, see repository_summary.
+CREATE TABLE IF NOT EXISTS repositories (
+    full_name      TEXT PRIMARY KEY, -- owner/name, in lower case
+    description    TEXT,
+    language       TEXT,
+    stars          INTEGER,
+    license        TEXT,
+    archived       INTEGER,
+    pushed_at      TEXT,
+    first_seen_run INTEGER NOT NULL REFERENCES runs (id),
+    last_seen_run  INTEGER NOT NULL REFERENCES runs (id)
+);
+
+-- issues has a row per issue or pull request ever found, with its values in
+-- the last run which found it. An issue not found by the latest run was
+-- closed, assigned or lost its labels in between.
+CREATE TABLE IF NOT EXISTS issues (
+    url            TEXT PRIMARY KEY, -- https://github.com/owner/name/issues/number
+    repository     TEXT    NOT NULL REFERENCES repositories (full_name),
+    number         INTEGER NOT NULL,
+    title          TEXT    NOT NULL,
+    pull_request   INTEGER NOT NULL, -- 1 for pull requests
+    created_at     TEXT,
+    updated_at     TEXT,
+    comments       INTEGER NOT NULL,
+    reactions      INTEGER NOT NULL,
+    score          REAL, -- NULL unless scoring is enabled
+    first_seen_run INTEGER NOT NULL REFERENCES runs (id),
+    last_seen_run  INTEGER NOT NULL REFERENCES runs (id)
+);
+
+-- labels has the labels of each issue in the last run which found it.
+CREATE TABLE IF NOT EXISTS labels (
+    issue_url TEXT NOT NULL REFERENCES issues (url),
+    name      TEXT NOT NULL,
+    PRIMARY KEY (issue_url, name)
+);
+
+-- run_issues lists the issues found by each run in each category.
+CREATE TABLE IF NOT EXISTS run_issues (
+    run_id    INTEGER NOT NULL REFERENCES runs (id),
+    category  TEXT    NOT NULL,
+    issue_url TEXT    NOT NULL REFERENCES issues (url),
+    PRIMARY KEY (run_id, category, issue_url)
+);
+
+CREATE INDEX IF NOT EXISTS run_issues_issue_url ON run_issues (issue_url);
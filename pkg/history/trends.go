@@ -0,0 +1,292 @@
+package history
+
+import (
+	"database/sql"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Trends summarizes the history over a number of weeks.
+type Trends struct {
+	// Runs is the number of runs in the period.
+	Runs int
+	// Weeks are the weeks of the period, oldest first, starting from the
+	// first week with a run.
+	Weeks []Week
+	// Categories are the categories found in the period, sorted.
+	Categories []string
+	// Repositories are the repositories with issues in the period, the ones
+	// with the most added and gone issues first.
+	Repositories []RepositoryTrend
+}
+
+// Week describes the issues found by the last run of a week, or of an
+// earlier week when no run happened, and the changes over the week.
+type Week struct {
+	Start time.Time
+	// Open and Age are the number and average age in days of the issues of
+	// each category.
+	Open map[string]int
+	Age  map[string]float64
+	// Total and AverageAge are the number and average age in days of the
+	// distinct issues of all categories.
+	Total      int
+	AverageAge float64
+	// Added is the number of issues found for the first time during the
+	// week, and Gone the number of issues not found anymore, because they
+	// were closed, assigned or lost their labels.
+	Added int
+	Gone  int
+}
+
+// RepositoryTrend describes the issues of a repository over the period.
+type RepositoryTrend struct {
+	Repository string
+	Added      int
+	Gone       int
+	// Open is the number of issues found by the latest run.
+	Open int
+	// MedianDaysOpen is the median number of days between the creation of
+	// the gone issues and the run not finding them anymore.
+	MedianDaysOpen float64
+}
+
+type run struct {
+	id        int64
+	startedAt time.Time
+}
+
+type issueRow struct {
+	url, repository string
+	createdAt       time.Time
+	firstSeen       int64
+	lastSeen        int64
+}
+
+// querier is implemented by *sql.DB and *sql.Tx, so that the trends of a run
+// can be computed before it is committed.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// Trends computes the trends of the weeks weeks up to the one of now. Weeks
+// start on Monday, in UTC.
+func (h *DB) Trends(now time.Time, weeks int) (*Trends, error) {
+	return trends(h.db, now, weeks)
+}
+
+// Trends computes the trends like DB.Trends, including the run.
+func (r *Run) Trends(now time.Time, weeks int) (*Trends, error) {
+	return trends(r.tx, now, weeks)
+}
+
+func trends(q querier, now time.Time, weeks int) (*Trends, error) {
+	runs, err := queryRuns(q)
+	if err != nil {
+		return nil, err
+	}
+	t := &Trends{}
+	if len(runs) == 0 {
+		return t, nil
+	}
+	issues, err := queryIssues(q)
+	if err != nil {
+		return nil, err
+	}
+
+	start := weekStart(now).AddDate(0, 0, -7*(weeks-1))
+	for _, r := range runs {
+		if !r.startedAt.Before(start) {
+			t.Runs++
+		}
+	}
+
+	// An issue is gone at the first run after the last one finding it.
+	// Issues of the first run ever are not counted as added.
+	goneAt := make(map[string]time.Time)
+	latest := runs[len(runs)-1].id
+	for _, issue := range issues {
+		if issue.lastSeen == latest {
+			continue
+		}
+		i, _ := slices.BinarySearchFunc(runs, issue.lastSeen+1, func(r run, id int64) int { return int(r.id - id) })
+		goneAt[issue.url] = runs[i].startedAt
+	}
+	runAt := make(map[int64]time.Time, len(runs))
+	for _, r := range runs {
+		runAt[r.id] = r.startedAt
+	}
+
+	categories := make(map[string]bool)
+	for w := start; !w.After(now); w = w.AddDate(0, 0, 7) {
+		end := w.AddDate(0, 0, 7)
+		last := -1
+		for i, r := range runs {
+			if r.startedAt.Before(end) {
+				last = i
+			}
+		}
+		if last < 0 {
+			continue
+		}
+		week, err := queryWeek(q, runs[last], issues)
+		if err != nil {
+			return nil, err
+		}
+		week.Start = w
+		for _, issue := range issues {
+			if at := runAt[issue.firstSeen]; issue.firstSeen != runs[0].id && inWeek(at, w) {
+				week.Added++
+			}
+			if at, ok := goneAt[issue.url]; ok && inWeek(at, w) {
+				week.Gone++
+			}
+		}
+		for k := range week.Open {
+			categories[k] = true
+		}
+		t.Weeks = append(t.Weeks, week)
+	}
+	t.Categories = slices.Sorted(maps.Keys(categories))
+
+	repos := make(map[string]*RepositoryTrend)
+	days := make(map[string][]float64)
+	repo := func(name string) *RepositoryTrend {
+		if repos[name] == nil {
+			repos[name] = &RepositoryTrend{Repository: name}
+		}
+		return repos[name]
+	}
+	for _, issue := range issues {
+		if issue.lastSeen == latest {
+			repo(issue.repository).Open++
+		}
+		if at := runAt[issue.firstSeen]; issue.firstSeen != runs[0].id && !at.Before(start) {
+			repo(issue.repository).Added++
+		}
+		if at, ok := goneAt[issue.url]; ok && !at.Before(start) {
+			repo(issue.repository).Gone++
+			days[issue.repository] = append(days[issue.repository], at.Sub(issue.createdAt).Hours()/24)
+		}
+	}
+	for name, r := range repos {
+		r.MedianDaysOpen = median(days[name])
+		t.Repositories = append(t.Repositories, *r)
+	}
+	slices.SortFunc(t.Repositories, func(a, b RepositoryTrend) int {
+		if d := (b.Added + b.Gone) - (a.Added + a.Gone); d != 0 {
+			return d
+		}
+		return strings.Compare(a.Repository, b.Repository)
+	})
+
+	return t, nil
+}
+
+// queryWeek returns the issues found by r.
+func queryWeek(q querier, r run, issues map[string]issueRow) (Week, error) {
+	rows, err := q.Query(`SELECT category, issue_url FROM run_issues WHERE run_id = ?`, r.id)
+	if err != nil {
+		return Week{}, fmt.Errorf("failed to read run %d: %w", r.id, err)
+	}
+	defer rows.Close()
+
+	w := Week{Open: make(map[string]int), Age: make(map[string]float64)}
+	distinct := make(map[string]bool)
+	for rows.Next() {
+		var category, url string
+		if err := rows.Scan(&category, &url); err != nil {
+			return Week{}, err
+		}
+		age := r.startedAt.Sub(issues[url].createdAt).Hours() / 24
+		w.Open[category]++
+		w.Age[category] += age
+		if !distinct[url] {
+			distinct[url] = true
+			w.Total++
+			w.AverageAge += age
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return Week{}, err
+	}
+
+	for k, n := range w.Open {
+		w.Age[k] /= float64(n)
+	}
+	if w.Total > 0 {
+		w.AverageAge /= float64(w.Total)
+	}
+	return w, nil
+}
+
+func queryRuns(q querier) ([]run, error) {
+	rows, err := q.Query(`SELECT id, started_at FROM runs ORDER BY id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read runs: %w", err)
+	}
+	defer rows.Close()
+
+	var runs []run
+	for rows.Next() {
+		var r run
+		var startedAt string
+		if err := rows.Scan(&r.id, &startedAt); err != nil {
+			return nil, err
+		}
+		if r.startedAt, err = time.Parse(time.RFC3339, startedAt); err != nil {
+			return nil, fmt.Errorf("invalid start time of run %d: %w", r.id, err)
+		}
+		runs = append(runs, r)
+	}
+	return runs, rows.Err()
+}
+
+func queryIssues(q querier) (map[string]issueRow, error) {
+	rows, err := q.Query(`SELECT url, repository, created_at, first_seen_run, last_seen_run FROM issues`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read issues: %w", err)
+	}
+	defer rows.Close()
+
+	issues := make(map[string]issueRow)
+	for rows.Next() {
+		var i issueRow
+		var createdAt sql.NullString
+		if err := rows.Scan(&i.url, &i.repository, &createdAt, &i.firstSeen, &i.lastSeen); err != nil {
+			return nil, err
+		}
+		if createdAt.Valid {
+			if i.createdAt, err = time.Parse(time.RFC3339, createdAt.String); err != nil {
+				return nil, fmt.Errorf("invalid creation time of %s: %w", i.url, err)
+			}
+		}
+		issues[i.url] = i
+	}
+	return issues, rows.Err()
+}
+
+// weekStart returns the Monday starting the week of t, in UTC.
+func weekStart(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+}
+
+func inWeek(t, start time.Time) bool {
+	return !t.Before(start) && t.Before(start.AddDate(0, 0, 7))
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	values = slices.Sorted(slices.Values(values))
+	if n := len(values); n%2 == 0 {
+		return (values[n/2-1] + values[n/2]) / 2
+	}
+	return values[len(values)/2]
+}
@@ -0,0 +1,98 @@
+package history
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/client"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+func TestTrends(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	date := func(month time.Month, day int) time.Time {
+		return time.Date(2025, month, day, 0, 0, 0, 0, time.UTC)
+	}
+	a := newIssue("https://github.com/owner/x/issues/1", "A", date(2, 10))
+	b := newIssue("https://github.com/owner/y/issues/1", "B", date(2, 19))
+	c := newIssue("https://github.com/owner/x/issues/2", "C", date(3, 1))
+	d := newIssue("https://github.com/owner/y/issues/2", "D", date(3, 16))
+
+	// Before the period, then in the weeks of March 3rd and 17th
+	for _, r := range []struct {
+		at     time.Time
+		issues client.Issues
+	}{
+		{date(2, 20), client.Issues{"a": {a, b}}},
+		{date(3, 4), client.Issues{"a": {a, c}, "b": {c}}},
+		{date(3, 17), client.Issues{"a": {c, d}, "b": {c}}},
+	} {
+		_, err := db.Record(&config.Config{}, r.at, r.issues, client.Repositories{})
+		assert.NoError(t, err)
+	}
+
+	got, err := db.Trends(date(3, 19), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, &Trends{
+		Runs: 2,
+		Weeks: []Week{
+			{
+				Start: date(3, 3),
+				Open:  map[string]int{"a": 2, "b": 1},
+				Age:   map[string]float64{"a": 12.5, "b": 3},
+				Total: 2, AverageAge: 12.5,
+				Added: 1, Gone: 1,
+			},
+			{
+				Start: date(3, 10),
+				Open:  map[string]int{"a": 2, "b": 1},
+				Age:   map[string]float64{"a": 12.5, "b": 3},
+				Total: 2, AverageAge: 12.5,
+			},
+			{
+				Start: date(3, 17),
+				Open:  map[string]int{"a": 2, "b": 1},
+				Age:   map[string]float64{"a": 8.5, "b": 16},
+				Total: 2, AverageAge: 8.5,
+				Added: 1, Gone: 1,
+			},
+		},
+		Categories: []string{"a", "b"},
+		Repositories: []RepositoryTrend{
+			{Repository: "owner/x", Added: 1, Gone: 1, Open: 1, MedianDaysOpen: 35},
+			{Repository: "owner/y", Added: 1, Gone: 1, Open: 1, MedianDaysOpen: 13},
+		},
+	}, got)
+}
+
+func TestTrendsWithoutRuns(t *testing.T) {
+	db, err := Open(filepath.Join(t.TempDir(), "history.db"))
+	assert.NoError(t, err)
+	defer db.Close()
+
+	got, err := db.Trends(time.Now(), 12)
+	assert.NoError(t, err)
+	assert.Equal(t, &Trends{}, got)
+}
+
+func TestWeekStart(t *testing.T) {
+	tests := []struct {
+		in   time.Time
+		want time.Time
+	}{
+		{in: time.Date(2025, 3, 19, 15, 4, 5, 0, time.UTC), want: time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{in: time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC), want: time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+		{in: time.Date(2025, 3, 23, 23, 0, 0, 0, time.UTC), want: time.Date(2025, 3, 17, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in.String(), func(t *testing.T) {
+			assert.Equal(t, tt.want, weekStart(tt.in))
+		})
+	}
+}
@@ -0,0 +1,238 @@
+package labels
+
+import (
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Label is a label defined in a repository.
+type Label struct {
+	Repository  string
+	Name        string
+	Description string
+}
+
+// Variant is a spelling of a label and the repositories defining it.
+type Variant struct {
+	Name         string   `json:"name"`
+	Repositories []string `json:"repositories"`
+}
+
+// Cluster is a group of labels with the same intent.
+type Cluster struct {
+	// Name is the intent of the labels when known, otherwise the most common
+	// variant.
+	Name     string    `json:"name"`
+	Variants []Variant `json:"variants"`
+	// Repositories defines at least one of the variants.
+	Repositories []string `json:"repositories"`
+}
+
+const (
+	// minWordLength is the length of the shortest word which may be misspelled.
+	minWordLength = 4
+	// maxEdits is the number of misspelled words allowed in similar names.
+	maxEdits = 2
+)
+
+// intent is a well-known purpose of labels, recognized by keywords.
+type intent struct {
+	name     string
+	keywords []string
+}
+
+// intents are matched in order, the first matching one wins.
+var intents = []intent{
+	{"good first issue", []string{"good first issue", "first timers only", "beginner", "beginners", "easy", "starter", "newcomer", "newcomers", "newbie", "low hanging fruit"}},
+	{"help wanted", []string{"help wanted", "contributions welcome", "pr welcome", "prs welcome", "up for grabs", "call for participation"}},
+}
+
+// Fold normalizes case and punctuation: "Good-First_Issue" and "good first
+// issue" fold to the same name.
+func Fold(name string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}), " ")
+}
+
+// Group clusters labels by folded name, small edit distances and the intent
+// keywords found in their name or making up their description. Clusters are sorted by the
+// number of repositories, most common first.
+func Group(labels []Label) []Cluster {
+	// Nodes are folded names, and intents prefixed by a NUL byte which cannot
+	// appear in a folded name
+	parent := make(map[string]string)
+	var find func(string) string
+	find = func(n string) string {
+		if parent[n] == n {
+			return n
+		}
+		parent[n] = find(parent[n])
+		return parent[n]
+	}
+	union := func(a, b string) {
+		ra, rb := find(a), find(b)
+		// Keep intents as roots to name the clusters after them
+		if strings.HasPrefix(rb, "\x00") {
+			ra, rb = rb, ra
+		}
+		parent[rb] = ra
+	}
+
+	variants := make(map[string]map[string]map[string]struct{}) // folded name -> name -> repositories
+	for _, l := range labels {
+		f := Fold(l.Name)
+		if f == "" {
+			continue
+		}
+		if _, ok := parent[f]; !ok {
+			parent[f] = f
+			variants[f] = make(map[string]map[string]struct{})
+		}
+		if variants[f][l.Name] == nil {
+			variants[f][l.Name] = make(map[string]struct{})
+		}
+		variants[f][l.Name][l.Repository] = struct{}{}
+
+		if in := intentOf(l); in != "" {
+			node := "\x00" + in
+			if _, ok := parent[node]; !ok {
+				parent[node] = node
+			}
+			union(node, f)
+		}
+	}
+
+	folded := slices.Sorted(maps.Keys(variants))
+	for i, a := range folded {
+		for _, b := range folded[i+1:] {
+			if similar(a, b) {
+				union(a, b)
+			}
+		}
+	}
+
+	members := make(map[string][]string)
+	for _, f := range folded {
+		root := find(f)
+		members[root] = append(members[root], f)
+	}
+
+	clusters := make([]Cluster, 0, len(members))
+	for root, fs := range members {
+		var cl Cluster
+		repos := make(map[string]struct{})
+		for _, f := range fs {
+			for name, rs := range variants[f] {
+				cl.Variants = append(cl.Variants, Variant{Name: name, Repositories: slices.Sorted(maps.Keys(rs))})
+				maps.Copy(repos, rs)
+			}
+		}
+		sort.Slice(cl.Variants, func(i, j int) bool {
+			a, b := cl.Variants[i], cl.Variants[j]
+			if len(a.Repositories) != len(b.Repositories) {
+				return len(a.Repositories) > len(b.Repositories)
+			}
+			return a.Name < b.Name
+		})
+		cl.Repositories = slices.Sorted(maps.Keys(repos))
+		cl.Name = cl.Variants[0].Name
+		if name, ok := strings.CutPrefix(root, "\x00"); ok {
+			cl.Name = name
+		}
+		clusters = append(clusters, cl)
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		a, b := clusters[i], clusters[j]
+		if len(a.Repositories) != len(b.Repositories) {
+			return len(a.Repositories) > len(b.Repositories)
+		}
+		return a.Name < b.Name
+	})
+	return clusters
+}
+
+// Contains reports whether name is a variant of the cluster, ignoring case
+// and punctuation.
+func (c Cluster) Contains(name string) bool {
+	f := Fold(name)
+	return slices.ContainsFunc(c.Variants, func(v Variant) bool { return Fold(v.Name) == f })
+}
+
+// VariantsIn returns the variants defined in the repository.
+func (c Cluster) VariantsIn(repo string) []string {
+	var names []string
+	for _, v := range c.Variants {
+		if slices.Contains(v.Repositories, repo) {
+			names = append(names, v.Name)
+		}
+	}
+	return names
+}
+
+// intentOf returns the intent of the label found in its name, or else in its
+// description. Descriptions are free text ("easy doc fixes" is about docs), so
+// they must be a keyword as a whole, while names only have to contain one.
+func intentOf(l Label) string {
+	words := " " + Fold(l.Name) + " "
+	description := Fold(l.Description)
+	for _, in := range intents {
+		for _, kw := range in.keywords {
+			if strings.Contains(words, " "+kw+" ") {
+				return in.name
+			}
+		}
+	}
+	for _, in := range intents {
+		if slices.Contains(in.keywords, description) {
+			return in.name
+		}
+	}
+	return ""
+}
+
+// similar reports whether two folded names are likely typos or plural forms
+// of each other: they have the same words, up to one edit in words of at
+// least minWordLength letters, so that "size s" and "size m" differ.
+func similar(a, b string) bool {
+	wa, wb := strings.Fields(a), strings.Fields(b)
+	if len(wa) != len(wb) {
+		return false
+	}
+	edits := 0
+	for i := range wa {
+		if wa[i] == wb[i] {
+			continue
+		}
+		if min(len(wa[i]), len(wb[i])) < minWordLength || distance(wa[i], wb[i]) > 1 {
+			return false
+		}
+		edits++
+	}
+	return edits <= maxEdits
+}
+
+// distance is the Levenshtein distance between a and b.
+func distance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
@@ -0,0 +1,118 @@
+package labels
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name  string
+		label string
+		want  string
+	}{
+		{"spaces", "good first issue", "good first issue"},
+		{"case and dashes", "Good-First-Issue", "good first issue"},
+		{"prefix", "difficulty: easy", "difficulty easy"},
+		{"emoji", "🐛 Bug", "bug"},
+		{"punctuation only", "---", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Fold(tt.label))
+		})
+	}
+}
+
+func TestSimilar(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"good first issue", "good first issues", true},
+		{"feature", "features", true},
+		{"help wanted", "help wanted", true},
+		{"size s", "size m", false},
+		{"bug", "bugs", false},
+		{"area docs", "area core", false},
+		{"good first issue", "first issue", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, similar(tt.a, tt.b))
+		})
+	}
+}
+
+func TestGroup(t *testing.T) {
+	labels := []Label{
+		{Repository: "a/a", Name: "good first issue", Description: "Good for newcomers"},
+		{Repository: "a/a", Name: "help wanted"},
+		{Repository: "a/a", Name: "bug"},
+		{Repository: "b/b", Name: "Good-First-Issue"},
+		{Repository: "b/b", Name: "help-wanted"},
+		{Repository: "c/c", Name: "E-easy", Description: "Call for participation: Easy difficulty"},
+		{Repository: "c/c", Name: "E-help-wanted"},
+		{Repository: "c/c", Name: "bugs"},
+		{Repository: "d/d", Name: "difficulty: easy"},
+		{Repository: "d/d", Name: "up for grabs"},
+		{Repository: "d/d", Name: "docs", Description: "Easy doc fixes"},
+		{Repository: "e/e", Name: "contrib", Description: "Help wanted"},
+	}
+
+	want := []Cluster{
+		{
+			Name: "help wanted",
+			Variants: []Variant{
+				{Name: "E-help-wanted", Repositories: []string{"c/c"}},
+				{Name: "contrib", Repositories: []string{"e/e"}},
+				{Name: "help wanted", Repositories: []string{"a/a"}},
+				{Name: "help-wanted", Repositories: []string{"b/b"}},
+				{Name: "up for grabs", Repositories: []string{"d/d"}},
+			},
+			Repositories: []string{"a/a", "b/b", "c/c", "d/d", "e/e"},
+		},
+		{
+			Name: "good first issue",
+			Variants: []Variant{
+				{Name: "E-easy", Repositories: []string{"c/c"}},
+				{Name: "Good-First-Issue", Repositories: []string{"b/b"}},
+				{Name: "difficulty: easy", Repositories: []string{"d/d"}},
+				{Name: "good first issue", Repositories: []string{"a/a"}},
+			},
+			Repositories: []string{"a/a", "b/b", "c/c", "d/d"},
+		},
+		{
+			Name: "bug",
+			Variants: []Variant{
+				{Name: "bug", Repositories: []string{"a/a"}},
+			},
+			Repositories: []string{"a/a"},
+		},
+		{
+			Name: "bugs",
+			Variants: []Variant{
+				{Name: "bugs", Repositories: []string{"c/c"}},
+			},
+			Repositories: []string{"c/c"},
+		},
+		// Keywords in free text descriptions are not an intent
+		{
+			Name: "docs",
+			Variants: []Variant{
+				{Name: "docs", Repositories: []string{"d/d"}},
+			},
+			Repositories: []string{"d/d"},
+		},
+	}
+	got := Group(labels)
+	assert.Equal(t, want, got)
+
+	assert.True(t, got[1].Contains("GOOD FIRST ISSUE"))
+	assert.False(t, got[1].Contains("help wanted"))
+	assert.Equal(t, []string{"E-easy"}, got[1].VariantsIn("c/c"))
+	assert.Nil(t, got[1].VariantsIn("e/e"))
+}
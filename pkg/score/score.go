@@ -0,0 +1,149 @@
+package score
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+const (
+	// bodyLengthForFullScore is the description length, in characters, which
+	// gets the full body signal.
+	bodyLengthForFullScore = 800
+	// commentsForHalfScore is the number of comments which halves the
+	// comments signal.
+	commentsForHalfScore = 5
+)
+
+var (
+	checklistPattern    = regexp.MustCompile(`(?m)^\s*[-*] \[[ xX]\]`)
+	reproductionPattern = regexp.MustCompile(`(?i)steps to reproduce|how to reproduce|reproduction|expected behaviou?r|actual behaviou?r`)
+)
+
+// Input is what the scorer needs to know about an issue.
+type Input struct {
+	Labels    []string
+	Body      string
+	Comments  int
+	UpdatedAt time.Time
+	// RepoActivity is the share, 0-1, of recently updated issues in the
+	// repository of the issue.
+	RepoActivity float64
+}
+
+// Signals holds each signal of an issue, normalized to 0-1.
+type Signals struct {
+	Labels    float64
+	Recency   float64
+	Comments  float64
+	Body      float64
+	Structure float64
+	Activity  float64
+}
+
+type Scorer struct {
+	config config.Scoring
+	now    time.Time
+	// labels holds the label weights keyed by lower-cased label name.
+	labels   map[string]float64
+	maxLabel float64
+}
+
+func New(c config.Scoring, now time.Time) *Scorer {
+	s := &Scorer{
+		config: c,
+		now:    now,
+		labels: make(map[string]float64, len(c.Labels)),
+	}
+	for l, w := range c.Labels {
+		s.labels[strings.ToLower(l)] = w
+		s.maxLabel = math.Max(s.maxLabel, w)
+	}
+	return s
+}
+
+// Score returns the score of the issue between 0 and 100.
+func (s *Scorer) Score(in Input) float64 {
+	sig := s.Signals(in)
+	w := s.config.Weights
+
+	total := w.Labels + w.Recency + w.Comments + w.Body + w.Structure + w.Activity
+	if total == 0 {
+		return 0
+	}
+	sum := w.Labels*sig.Labels +
+		w.Recency*sig.Recency +
+		w.Comments*sig.Comments +
+		w.Body*sig.Body +
+		w.Structure*sig.Structure +
+		w.Activity*sig.Activity
+
+	return math.Round(sum/total*1000) / 10
+}
+
+func (s *Scorer) Signals(in Input) Signals {
+	return Signals{
+		Labels:    s.labelSignal(in.Labels),
+		Recency:   s.recencySignal(in.UpdatedAt),
+		Comments:  commentsSignal(in.Comments),
+		Body:      bodySignal(in.Body),
+		Structure: structureSignal(in.Body),
+		Activity:  clamp(in.RepoActivity),
+	}
+}
+
+func (s *Scorer) labelSignal(labels []string) float64 {
+	if s.maxLabel == 0 {
+		return 0
+	}
+	var best float64
+	for _, l := range labels {
+		best = math.Max(best, s.labels[strings.ToLower(l)])
+	}
+	return best / s.maxLabel
+}
+
+// recencySignal decays by half every RecencyHalfLife days.
+func (s *Scorer) recencySignal(updatedAt time.Time) float64 {
+	if updatedAt.IsZero() || s.config.RecencyHalfLife <= 0 {
+		return 0
+	}
+	days := s.now.Sub(updatedAt).Hours() / 24
+	if days < 0 {
+		days = 0
+	}
+	return math.Pow(0.5, days/float64(s.config.RecencyHalfLife))
+}
+
+// commentsSignal prefers issues with little discussion, which are less likely
+// to be contentious or already being worked on.
+func commentsSignal(comments int) float64 {
+	return 1 / (1 + float64(comments)/commentsForHalfScore)
+}
+
+func bodySignal(body string) float64 {
+	return clamp(float64(len([]rune(strings.TrimSpace(body)))) / bodyLengthForFullScore)
+}
+
+// structureSignal is the share of checklists, reproduction steps and code
+// blocks found in the body.
+func structureSignal(body string) float64 {
+	found := 0
+	if checklistPattern.MatchString(body) {
+		found++
+	}
+	if reproductionPattern.MatchString(body) {
+		found++
+	}
+	if strings.Contains(body, "```") {
+		found++
+	}
+	return float64(found) / 3
+}
+
+func clamp(v float64) float64 {
+	return math.Min(math.Max(v, 0), 1)
+}
@@ -0,0 +1,107 @@
+package score
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/ymtdzzz/issue-scouter/pkg/config"
+)
+
+var now = time.Date(2025, 3, 9, 10, 0, 0, 0, time.UTC)
+
+func testScoring() config.Scoring {
+	return config.Scoring{
+		Labels:          map[string]float64{"good first issue": 3, "help wanted": 1},
+		RecencyHalfLife: 30,
+		Weights: config.ScoringWeights{
+			Labels:    3,
+			Recency:   2,
+			Comments:  1,
+			Body:      1,
+			Structure: 1,
+			Activity:  1,
+		},
+	}
+}
+
+func TestSignals(t *testing.T) {
+	tests := []struct {
+		name  string
+		input Input
+		want  Signals
+	}{
+		{
+			name:  "empty issue",
+			input: Input{},
+			want:  Signals{Comments: 1},
+		},
+		{
+			name: "labels are matched case-insensitively and the best wins",
+			input: Input{
+				Labels:    []string{"bug", "Help Wanted", "Good First Issue"},
+				UpdatedAt: now,
+			},
+			want: Signals{Labels: 1, Recency: 1, Comments: 1},
+		},
+		{
+			name: "lower label weight is relative to the highest",
+			input: Input{
+				Labels:    []string{"help wanted"},
+				UpdatedAt: now.Add(-30 * 24 * time.Hour),
+				Comments:  5,
+			},
+			want: Signals{Labels: 1.0 / 3, Recency: 0.5, Comments: 0.5},
+		},
+		{
+			name: "structured body",
+			input: Input{
+				Body:         "## Steps to reproduce\n\n- [ ] run it\n\n```sh\nmake\n```\n",
+				RepoActivity: 1.5,
+			},
+			want: Signals{Comments: 1, Body: 51.0 / 800, Structure: 1, Activity: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := New(testScoring(), now).Signals(tt.input)
+			assert.InDelta(t, tt.want.Labels, got.Labels, 1e-9)
+			assert.InDelta(t, tt.want.Recency, got.Recency, 1e-9)
+			assert.InDelta(t, tt.want.Comments, got.Comments, 1e-9)
+			assert.InDelta(t, tt.want.Body, got.Body, 1e-9)
+			assert.InDelta(t, tt.want.Structure, got.Structure, 1e-9)
+			assert.InDelta(t, tt.want.Activity, got.Activity, 1e-9)
+		})
+	}
+}
+
+func TestScore(t *testing.T) {
+	s := New(testScoring(), now)
+
+	best := s.Score(Input{
+		Labels:       []string{"good first issue"},
+		Body:         "## Steps to reproduce\n- [ ] a\n```go\n```\n" + strings.Repeat("a", 800),
+		UpdatedAt:    now,
+		RepoActivity: 1,
+	})
+	assert.Equal(t, 100.0, best)
+
+	gfi := s.Score(Input{Labels: []string{"good first issue"}, UpdatedAt: now.Add(-24 * time.Hour)})
+	hw := s.Score(Input{Labels: []string{"help wanted"}, UpdatedAt: now.Add(-24 * time.Hour)})
+	assert.Greater(t, gfi, hw)
+
+	fresh := s.Score(Input{UpdatedAt: now})
+	stale := s.Score(Input{UpdatedAt: now.Add(-365 * 24 * time.Hour)})
+	assert.Greater(t, fresh, stale)
+
+	quiet := s.Score(Input{Comments: 0})
+	busy := s.Score(Input{Comments: 50})
+	assert.Greater(t, quiet, busy)
+}
+
+func TestScoreWithoutWeights(t *testing.T) {
+	s := New(config.Scoring{}, now)
+	assert.Equal(t, 0.0, s.Score(Input{Labels: []string{"good first issue"}, UpdatedAt: now}))
+}